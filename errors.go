@@ -6,6 +6,35 @@ import (
 
 // Error variables
 var (
-	ErrInvalidPosition = errors.New("invalid position")
-	ErrInvalidMove     = errors.New("invalid move")
+	ErrInvalidPosition        = errors.New("invalid position")
+	ErrInvalidMove            = errors.New("invalid move")
+	ErrWrongTurn        error = &moveError{reason: "not the turn of the piece"}
+	ErrOutOfBoard       error = &moveError{reason: "square out of the board", cause: ErrInvalidPosition}
+	ErrPieceNotFound    error = &moveError{reason: "piece not found at the source"}
+	ErrUnreachable      error = &moveError{reason: "destination is unreachable"}
+	ErrNotInHand        error = &moveError{reason: "piece not in hand"}
+	ErrInvalidDrop      error = &moveError{reason: "piece cannot be dropped there"}
+	ErrInvalidPromotion error = &moveError{reason: "invalid promotion"}
+	ErrNifu             error = &moveError{reason: "nifu"}
+	ErrUchifuzume       error = &moveError{reason: "uchifuzume"}
+	ErrSelfCheck        error = &moveError{reason: "king left in check"}
+	ErrInvalidNotation        = errors.New("invalid notation")
+	ErrAmbiguousMove          = errors.New("ambiguous move")
+	ErrNotSupported           = errors.New("not supported")
+	ErrInvalidTime            = errors.New("invalid time")
+	ErrNoState                = errors.New("no state")
 )
+
+// moveError is the reason of the invalid move, which matches ErrInvalidMove and the cause with errors.Is
+type moveError struct {
+	reason string
+	cause  error
+}
+
+func (e *moveError) Error() string {
+	return ErrInvalidMove.Error() + ": " + e.reason
+}
+
+func (e *moveError) Is(target error) bool {
+	return target == ErrInvalidMove || (e.cause != nil && target == e.cause)
+}
@@ -0,0 +1,329 @@
+package bod
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/logic"
+)
+
+// ErrInvalidFormat is error
+var ErrInvalidFormat = errors.New("invalid format")
+
+// black pieces of the board diagram
+var pieceMap = map[rune]shogi.Piece{
+	'歩': shogi.BFU,
+	'香': shogi.BKY,
+	'桂': shogi.BKE,
+	'銀': shogi.BGI,
+	'金': shogi.BKI,
+	'角': shogi.BKA,
+	'飛': shogi.BHI,
+	'玉': shogi.BOU,
+	'王': shogi.BOU,
+	'と': shogi.BTO,
+	'杏': shogi.BNY,
+	'圭': shogi.BNK,
+	'全': shogi.BNG,
+	'馬': shogi.BUM,
+	'龍': shogi.BRY,
+	'竜': shogi.BRY,
+}
+
+var pieceNames = map[shogi.RawPiece]string{
+	shogi.FU: "歩",
+	shogi.KY: "香",
+	shogi.KE: "桂",
+	shogi.GI: "銀",
+	shogi.KI: "金",
+	shogi.KA: "角",
+	shogi.HI: "飛",
+	shogi.OU: "玉",
+}
+
+var promotedNames = map[shogi.RawPiece]string{
+	shogi.FU: "と",
+	shogi.KY: "杏",
+	shogi.KE: "圭",
+	shogi.GI: "全",
+	shogi.KA: "馬",
+	shogi.HI: "龍",
+}
+
+var kanjiDigits = []rune("〇一二三四五六七八九")
+
+// Write function writes the state as BOD
+func Write(w io.Writer, state shogi.State) error {
+	return WriteWithNames(w, state, [2]string{"先手", "後手"})
+}
+
+// WriteWithNames function writes the state as BOD with the names of the players such as "下手" and "上手"
+func WriteWithNames(w io.Writer, state shogi.State, names [2]string) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%sの持駒：%s\n", names[1], capturedString(state.GetCaptured(shogi.TurnWhite)))
+	bw.WriteString("  ９ ８ ７ ６ ５ ４ ３ ２ １\n")
+	bw.WriteString("+---------------------------+\n")
+	for rank := 1; rank <= 9; rank++ {
+		bw.WriteRune('|')
+		for file := 9; file >= 1; file-- {
+			piece, err := state.GetPiece(file, rank)
+			if err != nil {
+				return err
+			}
+			if piece == shogi.EMP {
+				bw.WriteString(" ・")
+				continue
+			}
+			if piece.Turn() == shogi.TurnWhite {
+				bw.WriteRune('v')
+			} else {
+				bw.WriteRune(' ')
+			}
+			if piece.IsPromoted() {
+				bw.WriteString(promotedNames[piece.Raw()])
+			} else {
+				bw.WriteString(pieceNames[piece.Raw()])
+			}
+		}
+		fmt.Fprintf(bw, "|%s\n", kanjiNumber(rank))
+	}
+	bw.WriteString("+---------------------------+\n")
+	fmt.Fprintf(bw, "%sの持駒：%s\n", names[0], capturedString(state.GetCaptured(shogi.TurnBlack)))
+	switch state.Turn() {
+	case shogi.TurnBlack:
+		fmt.Fprintf(bw, "%s番\n", names[0])
+	case shogi.TurnWhite:
+		fmt.Fprintf(bw, "%s番\n", names[1])
+	}
+	return bw.Flush()
+}
+
+// String function returns the state as BOD
+func String(state shogi.State) string {
+	b := &bytes.Buffer{}
+	Write(b, state)
+	return b.String()
+}
+
+// Parse function parses BOD and returns the state and the move number of the "手数＝" trailer, or 0 if it is absent
+func Parse(r io.Reader) (*logic.State, int, error) {
+	board := [9][9]shogi.Piece{}
+	captured := [2]shogi.Captured{}
+	turn := shogi.TurnBlack
+	turnFound := false
+	rows, moveNumber := 0, 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case len(trimmed) == 0:
+			continue
+		case strings.HasPrefix(line, "|"):
+			if rows > 8 {
+				return nil, 0, ErrInvalidFormat
+			}
+			row, err := ParseRow(line)
+			if err != nil {
+				return nil, 0, err
+			}
+			board[rows] = row
+			rows++
+		case strings.HasPrefix(trimmed, "９ ８ ７"), strings.HasPrefix(trimmed, "+-"):
+			continue
+		case trimmed == "先手番" || trimmed == "下手番":
+			turn, turnFound = shogi.TurnBlack, true
+		case trimmed == "後手番" || trimmed == "上手番":
+			turn, turnFound = shogi.TurnWhite, true
+		case strings.HasPrefix(trimmed, "手数＝"):
+			body := strings.TrimPrefix(trimmed, "手数＝")
+			end := strings.IndexFunc(body, func(r rune) bool { return r < '0' || r > '9' })
+			if end < 0 {
+				end = len(body)
+			}
+			n, err := strconv.Atoi(body[:end])
+			if err != nil {
+				return nil, 0, ErrInvalidFormat
+			}
+			moveNumber = n
+			// the side which made the last move
+			if !turnFound {
+				if strings.ContainsRune(body, '▲') {
+					turn = shogi.TurnWhite
+				} else if strings.ContainsRune(body, '△') {
+					turn = shogi.TurnBlack
+				}
+			}
+		case strings.Contains(trimmed, "持駒："):
+			idx := strings.Index(trimmed, "持駒：")
+			key, value := trimmed[:idx], trimmed[idx+len("持駒："):]
+			c, err := ParseCaptured(value)
+			if err != nil {
+				return nil, 0, err
+			}
+			switch strings.TrimSuffix(key, "の") {
+			case "先手", "下手":
+				captured[0] = c
+			case "後手", "上手":
+				captured[1] = c
+			default:
+				return nil, 0, ErrInvalidFormat
+			}
+		default:
+			return nil, 0, ErrInvalidFormat
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+	if rows != 9 {
+		return nil, 0, ErrInvalidFormat
+	}
+	return logic.NewState(board, captured, turn), moveNumber, nil
+}
+
+// ParseString function
+func ParseString(s string) (*logic.State, int, error) {
+	return Parse(strings.NewReader(s))
+}
+
+// ParseRow function parses a row of the board such as "|v香v桂v銀v金v玉v金v銀v桂v香|一"
+func ParseRow(line string) ([9]shogi.Piece, error) {
+	row := [9]shogi.Piece{}
+	end := strings.LastIndex(line, "|")
+	if !strings.HasPrefix(line, "|") || end <= 0 {
+		return row, ErrInvalidFormat
+	}
+	runes := []rune(line[1:end])
+	if len(runes) != 18 {
+		return row, ErrInvalidFormat
+	}
+	for j := 0; j < 9; j++ {
+		if runes[j*2+1] == '・' {
+			continue
+		}
+		piece, ok := pieceMap[runes[j*2+1]]
+		if !ok {
+			return row, ErrInvalidFormat
+		}
+		if runes[j*2] == 'v' {
+			piece = shogi.MakePiece(piece.Raw(), shogi.TurnWhite)
+			if pieceMap[runes[j*2+1]].IsPromoted() {
+				piece = piece.Promote()
+			}
+		}
+		row[j] = piece
+	}
+	return row, nil
+}
+
+// ParseCaptured function parses the pieces in hand such as "飛　角　歩十二" or "なし"
+func ParseCaptured(s string) (shogi.Captured, error) {
+	c := shogi.Captured{}
+	s = strings.TrimSpace(s)
+	if s == "なし" || s == "" {
+		return c, nil
+	}
+	for _, item := range strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == '　' }) {
+		runes := []rune(item)
+		piece, ok := pieceMap[runes[0]]
+		if !ok || piece.IsPromoted() || piece.Raw() == shogi.OU {
+			return c, ErrInvalidFormat
+		}
+		n := 1
+		if len(runes) > 1 {
+			n = parseKanjiNumber(string(runes[1:]))
+			if n <= 0 {
+				return c, ErrInvalidFormat
+			}
+		}
+		switch piece.Raw() {
+		case shogi.FU:
+			c.FU += n
+		case shogi.KY:
+			c.KY += n
+		case shogi.KE:
+			c.KE += n
+		case shogi.GI:
+			c.GI += n
+		case shogi.KI:
+			c.KI += n
+		case shogi.KA:
+			c.KA += n
+		case shogi.HI:
+			c.HI += n
+		}
+	}
+	return c, nil
+}
+
+func capturedString(c shogi.Captured) string {
+	items := []string{}
+	for _, e := range []struct {
+		raw shogi.RawPiece
+		num int
+	}{
+		{shogi.HI, c.HI}, {shogi.KA, c.KA}, {shogi.KI, c.KI}, {shogi.GI, c.GI}, {shogi.KE, c.KE}, {shogi.KY, c.KY}, {shogi.FU, c.FU},
+	} {
+		switch {
+		case e.num == 1:
+			items = append(items, pieceNames[e.raw])
+		case e.num > 1:
+			items = append(items, pieceNames[e.raw]+kanjiNumber(e.num))
+		}
+	}
+	if len(items) == 0 {
+		return "なし"
+	}
+	return strings.Join(items, "　")
+}
+
+// kanjiNumber formats numbers up to 99 in kanji numerals
+func kanjiNumber(n int) string {
+	s := ""
+	if n >= 10 {
+		if n >= 20 {
+			s += string(kanjiDigits[n/10])
+		}
+		s += "十"
+		n %= 10
+		if n == 0 {
+			return s
+		}
+	}
+	return s + string(kanjiDigits[n])
+}
+
+// parseKanjiNumber parses kanji numerals up to 99 such as "十二"
+func parseKanjiNumber(s string) int {
+	n := 0
+	for _, r := range s {
+		if r == '十' {
+			if n == 0 {
+				n = 1
+			}
+			n *= 10
+			continue
+		}
+		d := -1
+		for i, c := range kanjiDigits {
+			if c == r {
+				d = i
+			}
+		}
+		if r >= '1' && r <= '9' {
+			d = int(r - '0')
+		}
+		if d < 0 {
+			return -1
+		}
+		n = n - n%10 + d
+	}
+	return n
+}
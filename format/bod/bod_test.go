@@ -0,0 +1,132 @@
+package bod_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/format/bod"
+	"github.com/sugyan/shogi/format/csa"
+	"github.com/sugyan/shogi/logic"
+)
+
+const example = `後手の持駒：飛　桂　香　歩三
+  ９ ８ ７ ６ ５ ４ ３ ２ １
++---------------------------+
+|v香 ・ ・ ・ ・ ・ ・ ・ ・|一
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|二
+|v歩 ・ ・ ・v玉 ・ ・ ・ ・|三
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|四
+| ・ ・ ・ ・ 馬 ・ ・ ・ ・|五
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|六
+| ・ ・ ・ ・ ・ ・ ・v龍 ・|七
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|八
+| ・ ・ ・ ・ 玉 ・ ・ ・ ・|九
++---------------------------+
+先手の持駒：角　金四　銀四　桂三　香二　歩十二
+`
+
+func exampleState(turn shogi.Turn) *logic.State {
+	return logic.NewState(
+		[9][9]shogi.Piece{
+			{shogi.WKY, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.WFU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BUM, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WRY, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+		},
+		[2]shogi.Captured{
+			{FU: 12, KY: 2, KE: 3, GI: 4, KI: 4, KA: 1},
+			{FU: 3, KY: 1, KE: 1, HI: 1},
+		},
+		turn,
+	)
+}
+
+func TestString(t *testing.T) {
+	if s := bod.String(exampleState(shogi.TurnBlack)); s != example+"先手番\n" {
+		t.Errorf("got: %v", s)
+	}
+	if s := bod.String(exampleState(shogi.TurnWhite)); s != example+"後手番\n" {
+		t.Errorf("got: %v", s)
+	}
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		data       string
+		turn       shogi.Turn
+		moveNumber int
+	}{
+		{example, shogi.TurnBlack, 0},
+		{example + "後手番\n", shogi.TurnWhite, 0},
+		{example + "手数＝30  ▲５五馬  まで\n", shogi.TurnWhite, 30},
+		{example + "手数＝31  △２七龍  まで\n", shogi.TurnBlack, 31},
+		{example + "手数＝31  △２七龍  まで\n\n後手番\n", shogi.TurnWhite, 31},
+	}
+	for i, tc := range testCases {
+		state, moveNumber, err := bod.ParseString(tc.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !state.Equals(exampleState(tc.turn)) {
+			t.Errorf("#%d: got: %v, expected: %v", i, state, exampleState(tc.turn))
+		}
+		if moveNumber != tc.moveNumber {
+			t.Errorf("#%d: move number got: %d, expected: %d", i, moveNumber, tc.moveNumber)
+		}
+	}
+
+	for i, data := range []string{
+		"",
+		"先手の持駒：なし\n",
+		"|v香 ・ ・ ・ ・ ・ ・ ・|一\n",
+		"|v香 ・ ・ ・ ・ ・ ・ ・ ・ X|一\n",
+		"先手の持駒：玉\n",
+		"先手の持駒：歩零\n",
+		"不明な行\n",
+	} {
+		if _, _, err := bod.ParseString(data); err != bod.ErrInvalidFormat {
+			t.Errorf("#%d: got error: %v, expected: %v", i, err, bod.ErrInvalidFormat)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	matches, err := filepath.Glob(filepath.Join("..", "..", "testdata", "*.csa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) == 0 {
+		t.Fatal("no testdata")
+	}
+	for _, path := range matches {
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		record, err := csa.Parse(file)
+		file.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		state := record.State.Clone()
+		for _, move := range record.Moves {
+			if err := state.Move(move); err != nil {
+				t.Fatal(err)
+			}
+			parsed, _, err := bod.ParseString(bod.String(state))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !parsed.Equals(state) {
+				t.Fatalf("%s: got: %v, expected: %v", path, parsed, state)
+			}
+		}
+	}
+}
@@ -5,6 +5,11 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sugyan/shogi"
 	"github.com/sugyan/shogi/logic"
@@ -23,6 +28,8 @@ const (
 	phase4
 )
 
+var durationRegexp = regexp.MustCompile(`^\d+(\.\d+)?$`)
+
 var pieceMap = map[string]shogi.Piece{
 	" * ": shogi.EMP,
 	"+FU": shogi.BFU, "-FU": shogi.WFU,
@@ -41,14 +48,61 @@ var pieceMap = map[string]shogi.Piece{
 	"+RY": shogi.BRY, "-RY": shogi.WRY,
 }
 
+var terminations = map[string]shogi.Termination{
+	"TORYO":           shogi.TerminationResign,
+	"CHUDAN":          shogi.TerminationAbort,
+	"SENNICHI":        shogi.TerminationSennichite,
+	"TIME_UP":         shogi.TerminationTimeUp,
+	"ILLEGAL_MOVE":    shogi.TerminationIllegalMove,
+	"+ILLEGAL_ACTION": shogi.TerminationIllegalAction,
+	"-ILLEGAL_ACTION": shogi.TerminationIllegalAction,
+	"KACHI":           shogi.TerminationDeclaration,
+	"HIKIWAKE":        shogi.TerminationDraw,
+	"MAX_MOVES":       shogi.TerminationMaxMoves,
+	"JISHOGI":         shogi.TerminationJishogi,
+	"TSUMI":           shogi.TerminationCheckmate,
+	"FUZUMI":          shogi.TerminationNoCheckmate,
+	"ERROR":           shogi.TerminationError,
+	"MATTA":           shogi.TerminationMatta,
+}
+
 type parser struct {
-	r io.Reader
+	scanner *bufio.Scanner
+}
+
+// Reader type reads the records one by one from CSA data containing multiple games separated by "/"
+type Reader struct {
+	scanner *bufio.Scanner
+}
+
+// NewReader function
+func NewReader(r io.Reader) *Reader {
+	return &Reader{scanner: newScanner(r)}
+}
+
+// Read method returns the next record, or io.EOF if no more records exist.
+// After an error, the rest of the broken record is skipped and the next Read starts from the next record.
+func (r *Reader) Read() (*shogi.Record, error) {
+	p := parser{scanner: r.scanner}
+	record, err := p.parse()
+	if err != nil && err != io.EOF {
+		for r.scanner.Scan() {
+			if strings.HasPrefix(r.scanner.Text(), "/") {
+				break
+			}
+		}
+	}
+	return record, err
 }
 
-// Parse function
+// Parse function parses the first record
 func Parse(r io.Reader) (*shogi.Record, error) {
-	p := parser{r: r}
-	return p.parse()
+	p := parser{scanner: newScanner(r)}
+	record, err := p.parse()
+	if err == io.EOF {
+		return newRecord(), nil
+	}
+	return record, err
 }
 
 // ParseString function
@@ -56,22 +110,44 @@ func ParseString(s string) (*shogi.Record, error) {
 	return Parse(bytes.NewBufferString(s))
 }
 
-func (p *parser) parse() (*shogi.Record, error) {
-	record := &shogi.Record{
-		Players: [2]*shogi.Player{},
-		State:   logic.NewState([9][9]shogi.Piece{}, [2]shogi.Captured{}, shogi.TurnBlack),
-		Moves:   []*shogi.Move{},
+func newRecord() *shogi.Record {
+	return &shogi.Record{
+		Players:   [2]*shogi.Player{},
+		State:     logic.NewState([9][9]shogi.Piece{}, [2]shogi.Captured{}, shogi.TurnBlack),
+		Moves:     []*shogi.Move{},
+		MoveInfos: []*shogi.MoveInfo{},
+		Comments:  []string{},
 	}
+}
+
+func (p *parser) parse() (*shogi.Record, error) {
+	record := newRecord()
 	phase := phase1
-	scanner := bufio.NewScanner(p.r)
-	for scanner.Scan() {
-		line := scanner.Text()
+	empty := true
+	for p.scanner.Scan() {
+		line := p.scanner.Text()
 		if len(line) == 0 {
 			continue
 		}
+		if line[0] == '/' { // separator of games
+			if empty {
+				continue
+			}
+			return record, nil
+		}
+		empty = false
 		switch line[0] {
 		case '\'': // comment
-			continue
+			comment := line[1:]
+			if len(record.MoveInfos) == 0 {
+				record.Comments = append(record.Comments, comment)
+				continue
+			}
+			info := record.MoveInfos[len(record.MoveInfos)-1]
+			info.Comments = append(info.Comments, comment)
+			if info.Evaluation == nil && strings.HasPrefix(comment, "**") {
+				info.Evaluation = parseEvaluation(comment[2:])
+			}
 		case 'V': // version
 			if phase != phase1 {
 				continue
@@ -92,6 +168,7 @@ func (p *parser) parse() (*shogi.Record, error) {
 			if phase != phase2 {
 				continue
 			}
+			parseMetadata(&record.Metadata, line[1:])
 		case 'P': // initial positions
 			switch line[1] {
 			case 'I':
@@ -189,24 +266,182 @@ func (p *parser) parse() (*shogi.Record, error) {
 			if len(line) == 1 {
 				// first move
 				phase = phase4
+				if line[0] == '-' {
+					record.State.SetTurn(shogi.TurnWhite)
+				} else {
+					record.State.SetTurn(shogi.TurnBlack)
+				}
 				continue
 			}
 			if phase != phase4 {
 				continue
 			}
-			src := shogi.Position{File: int(line[1] - '0'), Rank: int(line[2] - '0')}
-			dst := shogi.Position{File: int(line[3] - '0'), Rank: int(line[4] - '0')}
-			piece := pieceMap[string(line[0])+line[5:7]]
-			record.Moves = append(record.Moves, &shogi.Move{
-				Src:   src,
-				Dst:   dst,
-				Piece: piece,
-			})
+			move, err := parseMove(line)
+			if err != nil {
+				return nil, err
+			}
+			record.Moves = append(record.Moves, move)
+			record.MoveInfos = append(record.MoveInfos, &shogi.MoveInfo{Comments: []string{}})
 		case 'T': // consumed times
+			if phase != phase4 || len(record.MoveInfos) == 0 {
+				continue
+			}
+			d, err := parseDuration(line[1:])
+			if err != nil {
+				return nil, err
+			}
+			record.MoveInfos[len(record.MoveInfos)-1].Time = d
 		case '%': // special case
+			if record.Termination != shogi.TerminationNone {
+				continue
+			}
+			termination, ok := terminations[line[1:]]
+			if !ok {
+				// unknown special case is ignored
+				continue
+			}
+			turn := record.State.Turn()
+			if len(record.Moves)%2 == 1 {
+				turn = !turn
+			}
+			record.Termination = termination
+			switch line[1:] {
+			case "+ILLEGAL_ACTION":
+				record.Result = shogi.LoseResult(shogi.TurnBlack)
+			case "-ILLEGAL_ACTION":
+				record.Result = shogi.LoseResult(shogi.TurnWhite)
+			default:
+				record.Result = termination.Result(turn)
+			}
 		default:
 			return nil, ErrInvalidLine
 		}
 	}
+	if err := p.scanner.Err(); err != nil {
+		return nil, err
+	}
+	if empty {
+		return nil, io.EOF
+	}
 	return record, nil
 }
+
+// splitStatements is a split function for bufio.Scanner which splits the data into the lines,
+// and also the statements separated by "," except for comments, player names and meta info
+func splitStatements(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	separators := "\n,"
+	if len(data) > 0 && (data[0] == '\'' || data[0] == 'N' || data[0] == '$') {
+		separators = "\n"
+	}
+	if i := bytes.IndexAny(data, separators); i >= 0 {
+		return i + 1, bytes.TrimSuffix(data[:i], []byte("\r")), nil
+	}
+	if atEOF {
+		return len(data), bytes.TrimSuffix(data, []byte("\r")), nil
+	}
+	return 0, nil, nil
+}
+
+func newScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(splitStatements)
+	return scanner
+}
+
+// parseDuration parses the seconds such as "12" or "6.5"
+func parseDuration(s string) (time.Duration, error) {
+	if !durationRegexp.MatchString(s) {
+		return 0, ErrInvalidLine
+	}
+	seconds, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, ErrInvalidLine
+	}
+	return time.Duration(math.Round(seconds * float64(time.Second))), nil
+}
+
+func parseMove(s string) (*shogi.Move, error) {
+	if len(s) < 7 {
+		return nil, ErrInvalidLine
+	}
+	for i := 1; i < 5; i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return nil, ErrInvalidLine
+		}
+	}
+	piece, ok := pieceMap[string(s[0])+s[5:7]]
+	if !ok || piece == shogi.EMP {
+		return nil, ErrInvalidLine
+	}
+	return &shogi.Move{
+		Src:   shogi.Position{File: int(s[1] - '0'), Rank: int(s[2] - '0')},
+		Dst:   shogi.Position{File: int(s[3] - '0'), Rank: int(s[4] - '0')},
+		Piece: piece,
+	}, nil
+}
+
+func parseEvaluation(s string) *shogi.Evaluation {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil
+	}
+	score, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return nil
+	}
+	evaluation := &shogi.Evaluation{Score: score, PV: []*shogi.Move{}}
+	for _, field := range fields[1:] {
+		move, err := parseMove(field)
+		if err != nil || len(field) != 7 {
+			break
+		}
+		evaluation.PV = append(evaluation.PV, move)
+	}
+	return evaluation
+}
+
+func parseMetadata(metadata *shogi.Metadata, line string) {
+	i := strings.Index(line, ":")
+	if i < 0 {
+		// ignore the line without a value
+		return
+	}
+	key, value := line[:i], line[i+1:]
+	switch key {
+	case "EVENT":
+		metadata.Event = value
+	case "SITE":
+		metadata.Site = value
+	case "START_TIME":
+		t, err := shogi.ParseTime(value)
+		if err != nil {
+			// keep the value of the unknown format as is
+			setOther(metadata, key, value)
+			return
+		}
+		metadata.StartTime = t
+	case "END_TIME":
+		t, err := shogi.ParseTime(value)
+		if err != nil {
+			setOther(metadata, key, value)
+			return
+		}
+		metadata.EndTime = t
+	case "TIME_LIMIT":
+		metadata.TimeLimit = value
+	case "OPENING":
+		metadata.Opening = value
+	default:
+		setOther(metadata, key, value)
+	}
+}
+
+func setOther(metadata *shogi.Metadata, key, value string) {
+	if metadata.Others == nil {
+		metadata.Others = map[string]string{}
+	}
+	metadata.Others[key] = value
+}
@@ -1,7 +1,13 @@
 package csa_test
 
 import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/sugyan/shogi"
 	"github.com/sugyan/shogi/format/csa"
@@ -20,6 +26,9 @@ func TestParse(t *testing.T) {
 		if !a.State.Equals(b.State) {
 			return false
 		}
+		if !reflect.DeepEqual(a.Metadata, b.Metadata) {
+			return false
+		}
 		if len(a.Moves) != len(b.Moves) {
 			return false
 		}
@@ -79,6 +88,14 @@ T6
 					{Name: "NAKAHARA"},
 					{Name: "YONENAGA"},
 				},
+				Metadata: shogi.Metadata{
+					Event:     "13th World Computer Shogi Championship",
+					Site:      "KAZUSA ARC",
+					StartTime: time.Date(2003, 5, 3, 10, 30, 0, 0, time.UTC),
+					EndTime:   time.Date(2003, 5, 3, 11, 11, 5, 0, time.UTC),
+					TimeLimit: "00:25+00",
+					Opening:   "YAGURA",
+				},
 				State: logic.NewState(
 					[9][9]shogi.Piece{
 						{shogi.WKY, shogi.WKE, shogi.WGI, shogi.WKI, shogi.WOU, shogi.WKI, shogi.WGI, shogi.WKE, shogi.WKY},
@@ -161,3 +178,314 @@ P-00AL`,
 		}
 	}
 }
+
+func TestParseMetadata(t *testing.T) {
+	record, err := csa.ParseString("V2.2\n$NOTE\n$EVENT:test\n$START_TIME:2003年05月03日\n$END_TIME:unknown\nPI\n+\n+7776FU\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := shogi.Metadata{
+		Event:  "test",
+		Others: map[string]string{"START_TIME": "2003年05月03日", "END_TIME": "unknown"},
+	}
+	if !reflect.DeepEqual(record.Metadata, expected) {
+		t.Errorf("got: %v, expected: %v", record.Metadata, expected)
+	}
+	if len(record.Moves) != 1 {
+		t.Errorf("got %d moves, expected: 1", len(record.Moves))
+	}
+}
+
+func TestParseWhiteFirst(t *testing.T) {
+	for i, data := range []string{
+		"PI82HI\n-\n-3334FU\n+7776FU\n-2288UM\n",
+		"PI\n-\n-3334FU\n+7776FU\n-2288UM\n",
+	} {
+		record, err := csa.ParseString(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record.State.Turn() != shogi.TurnWhite {
+			t.Errorf("#%d: turn got: %v, expected: %v", i, record.State.Turn(), shogi.TurnWhite)
+		}
+		if err := record.State.Clone().Move(record.Moves...); err != nil {
+			t.Errorf("#%d: %v", i, err)
+		}
+	}
+}
+
+func TestParseTimes(t *testing.T) {
+	record, err := csa.ParseString(`V3.0
+PI
++
++2726FU
+T12
+-3334FU
+T6.5
++7776FU
+T0.001
+-8384FU
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []time.Duration{
+		12 * time.Second,
+		6500 * time.Millisecond,
+		time.Millisecond,
+		0,
+	}
+	if len(record.MoveInfos) != len(expected) {
+		t.Fatalf("got %d move infos, expected: %d", len(record.MoveInfos), len(expected))
+	}
+	for i, d := range expected {
+		if record.MoveInfos[i].Time != d {
+			t.Errorf("#%d: got: %v, expected: %v", i, record.MoveInfos[i].Time, d)
+		}
+	}
+	if total := record.TotalTime(shogi.TurnBlack); total != 12001*time.Millisecond {
+		t.Errorf("black total got: %v", total)
+	}
+	if total := record.TotalTime(shogi.TurnWhite); total != 6500*time.Millisecond {
+		t.Errorf("white total got: %v", total)
+	}
+	for _, data := range []string{
+		"PI\n+\n+2726FU\nTx\n",
+		"PI\n+\n+2726FU\nT-1\n",
+		"PI\n+\n+2726FU\nT1m30\n",
+		"PI\n+\n+2726FU\nT1h\n",
+		"PI\n+\n+2726FU\nT1e3\n",
+		"PI\n+\n+2726FU\nT+1\n",
+		"PI\n+\n+2726FU\nT.5\n",
+	} {
+		if _, err := csa.ParseString(data); err != csa.ErrInvalidLine {
+			t.Errorf("%q: got error: %v, expected: %v", data, err, csa.ErrInvalidLine)
+		}
+	}
+}
+
+func TestParseStatements(t *testing.T) {
+	record, err := csa.ParseString("V2.2\nN+a,b\nPI,+\r\n+2726FU,T12,-3334FU,T6\n'comment,with comma\n+7776FU,T1\n%TORYO\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Players[0].Name != "a,b" {
+		t.Errorf("player name got: %v", record.Players[0].Name)
+	}
+	expected := []time.Duration{12 * time.Second, 6 * time.Second, time.Second}
+	if len(record.MoveInfos) != len(expected) {
+		t.Fatalf("got %d move infos, expected: %d", len(record.MoveInfos), len(expected))
+	}
+	for i, d := range expected {
+		if record.MoveInfos[i].Time != d {
+			t.Errorf("#%d: got: %v, expected: %v", i, record.MoveInfos[i].Time, d)
+		}
+	}
+	if !reflect.DeepEqual(record.MoveInfos[1].Comments, []string{"comment,with comma"}) {
+		t.Errorf("comments got: %v", record.MoveInfos[1].Comments)
+	}
+	if record.Termination != shogi.TerminationResign {
+		t.Errorf("termination got: %v", record.Termination)
+	}
+}
+
+func TestParseResult(t *testing.T) {
+	testCases := []struct {
+		data        string
+		result      shogi.Result
+		termination shogi.Termination
+	}{
+		{"PI\n+\n+7776FU\n-3334FU\n%TORYO\n", shogi.ResultWhiteWin, shogi.TerminationResign},
+		{"PI\n+\n+7776FU\n%TORYO\n", shogi.ResultBlackWin, shogi.TerminationResign},
+		{"PI\n+\n+7776FU\n%TIME_UP\n", shogi.ResultBlackWin, shogi.TerminationTimeUp},
+		{"PI\n+\n+7776FU\n%KACHI\n", shogi.ResultWhiteWin, shogi.TerminationDeclaration},
+		{"PI\n+\n+7776FU\n%ILLEGAL_MOVE\n", shogi.ResultBlackWin, shogi.TerminationIllegalMove},
+		{"PI\n+\n+7776FU\n%+ILLEGAL_ACTION\n", shogi.ResultWhiteWin, shogi.TerminationIllegalAction},
+		{"PI\n+\n+7776FU\n%-ILLEGAL_ACTION\n", shogi.ResultBlackWin, shogi.TerminationIllegalAction},
+		{"PI\n+\n+7776FU\n%SENNICHI\n", shogi.ResultDraw, shogi.TerminationSennichite},
+		{"PI\n+\n+7776FU\n%HIKIWAKE\n", shogi.ResultDraw, shogi.TerminationDraw},
+		{"PI\n+\n+7776FU\n%CHUDAN\n", shogi.ResultAborted, shogi.TerminationAbort},
+		{"PI\n+\n+7776FU\n%ERROR\n", shogi.ResultAborted, shogi.TerminationError},
+		{"PI\n-\n-3334FU\n%TSUMI\n", shogi.ResultWhiteWin, shogi.TerminationCheckmate},
+		{"PI\n+\n+7776FU\n%FUZUMI\n", shogi.ResultNone, shogi.TerminationNoCheckmate},
+		{"PI\n+\n+7776FU\n%MATTA\n", shogi.ResultNone, shogi.TerminationMatta},
+		{"PI\n+\n+7776FU\n", shogi.ResultNone, shogi.TerminationNone},
+	}
+	for i, tc := range testCases {
+		record, err := csa.ParseString(tc.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record.Result != tc.result || record.Termination != tc.termination {
+			t.Errorf("#%d: got: %v (%v), expected: %v (%v)", i, record.Result, record.Termination, tc.result, tc.termination)
+		}
+		s, err := csa.String(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := csa.ParseString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed.Result != record.Result || parsed.Termination != record.Termination {
+			t.Errorf("#%d: round trip got: %v (%v), expected: %v (%v)", i, parsed.Result, parsed.Termination, record.Result, record.Termination)
+		}
+	}
+	record, err := csa.ParseString("PI\n+\n+7776FU\n%UNKNOWN\n%TORYO\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Result != shogi.ResultBlackWin || record.Termination != shogi.TerminationResign {
+		t.Errorf("got: %v (%v), expected: %v (%v)", record.Result, record.Termination, shogi.ResultBlackWin, shogi.TerminationResign)
+	}
+}
+
+func TestParseComments(t *testing.T) {
+	record, err := csa.ParseString(`'header
+V2.2
+'先手番
+PI
++
++7776FU
+'* 角道を開ける
+'** 30 -3334FU +2726FU -8384FU
+-3334FU
+T3
+'**評価値なし
++8822UM
+'** -120 -3122GI %TORYO
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(record.Comments, []string{"header", "先手番"}) {
+		t.Errorf("header comments got: %v", record.Comments)
+	}
+	expectedComments := [][]string{
+		{"* 角道を開ける", "** 30 -3334FU +2726FU -8384FU"},
+		{"**評価値なし"},
+		{"** -120 -3122GI %TORYO"},
+	}
+	expectedEvaluations := []*shogi.Evaluation{
+		{
+			Score: 30,
+			PV: []*shogi.Move{
+				{Src: shogi.Position{File: 3, Rank: 3}, Dst: shogi.Position{File: 3, Rank: 4}, Piece: shogi.WFU},
+				{Src: shogi.Position{File: 2, Rank: 7}, Dst: shogi.Position{File: 2, Rank: 6}, Piece: shogi.BFU},
+				{Src: shogi.Position{File: 8, Rank: 3}, Dst: shogi.Position{File: 8, Rank: 4}, Piece: shogi.WFU},
+			},
+		},
+		nil,
+		{
+			Score: -120,
+			PV: []*shogi.Move{
+				{Src: shogi.Position{File: 3, Rank: 1}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.WGI},
+			},
+		},
+	}
+	if len(record.MoveInfos) != len(expectedComments) {
+		t.Fatalf("got %d move infos, expected: %d", len(record.MoveInfos), len(expectedComments))
+	}
+	for i, info := range record.MoveInfos {
+		if !reflect.DeepEqual(info.Comments, expectedComments[i]) {
+			t.Errorf("#%d: comments got: %v, expected: %v", i, info.Comments, expectedComments[i])
+		}
+		if !reflect.DeepEqual(info.Evaluation, expectedEvaluations[i]) {
+			t.Errorf("#%d: evaluation got: %v, expected: %v", i, info.Evaluation, expectedEvaluations[i])
+		}
+	}
+	if record.MoveInfos[1].Time != 3*time.Second {
+		t.Errorf("time got: %v", record.MoveInfos[1].Time)
+	}
+	s, err := csa.String(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := csa.ParseString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed.Comments, record.Comments) {
+		t.Errorf("round trip header comments got: %v", parsed.Comments)
+	}
+	for i, info := range parsed.MoveInfos {
+		if !reflect.DeepEqual(info, record.MoveInfos[i]) {
+			t.Errorf("#%d: round trip got: %v, expected: %v", i, info, record.MoveInfos[i])
+		}
+	}
+}
+
+func TestReader(t *testing.T) {
+	matches, err := filepath.Glob(filepath.Join("..", "..", "testdata", "*.csa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) == 0 {
+		t.Fatal("no testdata")
+	}
+	b := &bytes.Buffer{}
+	expected := []*shogi.Record{}
+	for i, path := range matches {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		record, err := csa.ParseString(string(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected = append(expected, record)
+		if i > 0 {
+			b.WriteString("/\n")
+		}
+		b.Write(data)
+	}
+	b.WriteString("/\n")
+
+	reader := csa.NewReader(b)
+	for i := 0; ; i++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			if i != len(expected) {
+				t.Errorf("read %d records, expected: %d", i, len(expected))
+			}
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i >= len(expected) {
+			t.Fatalf("too many records")
+		}
+		if !recordEquals(record, expected[i]) {
+			t.Errorf("#%d: record mismatch", i)
+		}
+	}
+	if _, err := reader.Read(); err != io.EOF {
+		t.Errorf("got error: %v, expected: %v", err, io.EOF)
+	}
+
+	record, err := csa.ParseString("PI\n+\n+7776FU\n/\nPI\n+\n+2726FU\n-3334FU\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(record.Moves) != 1 {
+		t.Errorf("got %d moves, expected: 1", len(record.Moves))
+	}
+
+	reader = csa.NewReader(bytes.NewBufferString("PI\n+\n+7776FU\nXYZ\n-3334FU\n/\nPI\n+\n+2726FU\n/\n/\n"))
+	if _, err := reader.Read(); err != csa.ErrInvalidLine {
+		t.Errorf("got error: %v, expected: %v", err, csa.ErrInvalidLine)
+	}
+	record, err = reader.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	moves := []*shogi.Move{{Src: shogi.Position{File: 2, Rank: 7}, Dst: shogi.Position{File: 2, Rank: 6}, Piece: shogi.BFU}}
+	if !reflect.DeepEqual(record.Moves, moves) {
+		t.Errorf("got: %v, expected: %v", record.Moves, moves)
+	}
+	if _, err := reader.Read(); err != io.EOF {
+		t.Errorf("got error: %v, expected: %v", err, io.EOF)
+	}
+}
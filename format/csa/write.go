@@ -0,0 +1,181 @@
+package csa
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/logic"
+)
+
+// CSA format versions
+const (
+	Version22 = "2.2"
+	Version30 = "3.0"
+)
+
+var terminationCodes = map[shogi.Termination]string{
+	shogi.TerminationResign:        "TORYO",
+	shogi.TerminationAbort:         "CHUDAN",
+	shogi.TerminationSennichite:    "SENNICHI",
+	shogi.TerminationTimeUp:        "TIME_UP",
+	shogi.TerminationIllegalMove:   "ILLEGAL_MOVE",
+	shogi.TerminationIllegalAction: "+ILLEGAL_ACTION",
+	shogi.TerminationDeclaration:   "KACHI",
+	shogi.TerminationDraw:          "HIKIWAKE",
+	shogi.TerminationMaxMoves:      "MAX_MOVES",
+	shogi.TerminationJishogi:       "JISHOGI",
+	shogi.TerminationCheckmate:     "TSUMI",
+	shogi.TerminationNoCheckmate:   "FUZUMI",
+	shogi.TerminationError:         "ERROR",
+	shogi.TerminationMatta:         "MATTA",
+}
+
+// Write function writes the record in CSA format V2.2
+func Write(w io.Writer, record *shogi.Record) error {
+	return WriteVersion(w, record, Version22)
+}
+
+// WriteVersion function writes the record in CSA format of the version
+func WriteVersion(w io.Writer, record *shogi.Record, version string) error {
+	if record.State == nil {
+		return shogi.ErrNoState
+	}
+	if err := record.State.Clone().Move(record.Moves...); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	// version
+	fmt.Fprintf(bw, "V%s\n", version)
+	for _, comment := range record.Comments {
+		fmt.Fprintf(bw, "'%s\n", comment)
+	}
+	// player names
+	for i, sign := range []string{"+", "-"} {
+		if record.Players[i] != nil {
+			fmt.Fprintf(bw, "N%s%s\n", sign, record.Players[i].Name)
+		}
+	}
+	// meta info
+	if record.Metadata.Event != "" {
+		fmt.Fprintf(bw, "$EVENT:%s\n", record.Metadata.Event)
+	}
+	if record.Metadata.Site != "" {
+		fmt.Fprintf(bw, "$SITE:%s\n", record.Metadata.Site)
+	}
+	if !record.Metadata.StartTime.IsZero() {
+		fmt.Fprintf(bw, "$START_TIME:%s\n", record.Metadata.StartTime.Format(shogi.TimeLayouts[0]))
+	}
+	if !record.Metadata.EndTime.IsZero() {
+		fmt.Fprintf(bw, "$END_TIME:%s\n", record.Metadata.EndTime.Format(shogi.TimeLayouts[0]))
+	}
+	if record.Metadata.TimeLimit != "" {
+		fmt.Fprintf(bw, "$TIME_LIMIT:%s\n", record.Metadata.TimeLimit)
+	}
+	if record.Metadata.Opening != "" {
+		fmt.Fprintf(bw, "$OPENING:%s\n", record.Metadata.Opening)
+	}
+	keys := make([]string, 0, len(record.Metadata.Others))
+	for key := range record.Metadata.Others {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(bw, "$%s:%s\n", key, record.Metadata.Others[key])
+	}
+	// initial position
+	writePosition(bw, record.State)
+	// moves
+	withTime := false
+	for _, info := range record.MoveInfos {
+		if info != nil && info.Time > 0 {
+			withTime = true
+		}
+	}
+	for i, move := range record.Moves {
+		fmt.Fprintf(bw, "%s%d%d%d%d%s\n",
+			move.Piece.String()[:1], move.Src.File, move.Src.Rank, move.Dst.File, move.Dst.Rank, move.Piece.String()[1:])
+		if i >= len(record.MoveInfos) || record.MoveInfos[i] == nil {
+			continue
+		}
+		info := record.MoveInfos[i]
+		if withTime {
+			bw.WriteString(timeString(info.Time, version))
+			bw.WriteRune('\n')
+		}
+		for _, comment := range info.Comments {
+			fmt.Fprintf(bw, "'%s\n", comment)
+		}
+	}
+	// termination
+	if code, ok := terminationCodes[record.Termination]; ok {
+		if record.Termination == shogi.TerminationIllegalAction && record.Result == shogi.ResultBlackWin {
+			code = "-ILLEGAL_ACTION"
+		}
+		fmt.Fprintf(bw, "%%%s\n", code)
+	}
+	return bw.Flush()
+}
+
+// String function returns the record in CSA format V2.2
+func String(record *shogi.Record) (string, error) {
+	b := &bytes.Buffer{}
+	if err := Write(b, record); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func writePosition(bw *bufio.Writer, state shogi.State) {
+	if state.Equals(logic.NewInitialState()) {
+		bw.WriteString("PI\n+\n")
+		return
+	}
+	for rank := 1; rank <= 9; rank++ {
+		fmt.Fprintf(bw, "P%d", rank)
+		for file := 9; file >= 1; file-- {
+			piece, _ := state.GetPiece(file, rank)
+			if piece == shogi.EMP {
+				bw.WriteString(" * ")
+			} else {
+				bw.WriteString(piece.String())
+			}
+		}
+		bw.WriteRune('\n')
+	}
+	for i, turn := range []shogi.Turn{shogi.TurnBlack, shogi.TurnWhite} {
+		c := state.GetCaptured(turn)
+		if c.Total() == 0 {
+			continue
+		}
+		bw.WriteString([]string{"P+", "P-"}[i])
+		for _, e := range []struct {
+			code string
+			num  int
+		}{
+			{"HI", c.HI}, {"KA", c.KA}, {"KI", c.KI}, {"GI", c.GI}, {"KE", c.KE}, {"KY", c.KY}, {"FU", c.FU},
+		} {
+			for j := 0; j < e.num; j++ {
+				bw.WriteString("00" + e.code)
+			}
+		}
+		bw.WriteRune('\n')
+	}
+	switch state.Turn() {
+	case shogi.TurnBlack:
+		bw.WriteString("+\n")
+	case shogi.TurnWhite:
+		bw.WriteString("-\n")
+	}
+}
+
+func timeString(d time.Duration, version string) string {
+	if version == Version30 && d%time.Second != 0 {
+		return fmt.Sprintf("T%.3f", d.Seconds())
+	}
+	return fmt.Sprintf("T%d", d/time.Second)
+}
@@ -0,0 +1,218 @@
+package csa_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/format/csa"
+	"github.com/sugyan/shogi/logic"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		record   *shogi.Record
+		version  string
+		expected string
+	}{
+		{
+			&shogi.Record{
+				Players: [2]*shogi.Player{
+					{Name: "NAKAHARA"},
+					{Name: "YONENAGA"},
+				},
+				State: logic.NewInitialState(),
+				Moves: []*shogi.Move{
+					{Src: shogi.Position{File: 2, Rank: 7}, Dst: shogi.Position{File: 2, Rank: 6}, Piece: shogi.BFU},
+					{Src: shogi.Position{File: 3, Rank: 3}, Dst: shogi.Position{File: 3, Rank: 4}, Piece: shogi.WFU},
+				},
+				MoveInfos: []*shogi.MoveInfo{
+					{Time: 12 * time.Second},
+					{Time: 6500 * time.Millisecond},
+				},
+				Metadata: shogi.Metadata{
+					Event:     "13th World Computer Shogi Championship",
+					StartTime: time.Date(2003, 5, 3, 10, 30, 0, 0, time.UTC),
+					TimeLimit: "00:25+00",
+					Opening:   "YAGURA",
+					Others:    map[string]string{"MAX_MOVES": "256"},
+				},
+			},
+			csa.Version22,
+			`V2.2
+N+NAKAHARA
+N-YONENAGA
+$EVENT:13th World Computer Shogi Championship
+$START_TIME:2003/05/03 10:30:00
+$TIME_LIMIT:00:25+00
+$OPENING:YAGURA
+$MAX_MOVES:256
+PI
++
++2726FU
+T12
+-3334FU
+T6
+`,
+		},
+		{
+			&shogi.Record{
+				State: logic.NewState(
+					[9][9]shogi.Piece{
+						{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+						{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WKA, shogi.EMP},
+						{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+						{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+						{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+						{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+						{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+						{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+						{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					},
+					[2]shogi.Captured{
+						{FU: 2, KI: 1},
+						{HI: 1},
+					},
+					shogi.TurnWhite,
+				),
+				Moves: []*shogi.Move{
+					{Src: shogi.Position{File: 0, Rank: 0}, Dst: shogi.Position{File: 5, Rank: 5}, Piece: shogi.WHI},
+				},
+				MoveInfos: []*shogi.MoveInfo{
+					{Time: 1500 * time.Millisecond},
+				},
+			},
+			csa.Version30,
+			`V3.0
+P1 *  *  *  * -OU *  *  *  * 
+P2 *  *  *  *  *  *  * -KA * 
+P3 *  *  *  *  *  *  *  *  * 
+P4 *  *  *  *  *  *  *  *  * 
+P5 *  *  *  *  *  *  *  *  * 
+P6 *  *  *  *  *  *  *  *  * 
+P7 *  *  *  *  *  *  *  *  * 
+P8 *  *  *  *  *  *  *  *  * 
+P9 *  *  *  * +OU *  *  *  * 
+P+00KI00FU00FU
+P-00HI
+-
+-0055HI
+T1.500
+`,
+		},
+	}
+	for i, tc := range tests {
+		b := &bytes.Buffer{}
+		if err := csa.WriteVersion(b, tc.record, tc.version); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != tc.expected {
+			t.Errorf("#%d: got: %v, expected: %v", i, b.String(), tc.expected)
+		}
+		record, err := csa.ParseString(b.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !record.State.Equals(tc.record.State) {
+			t.Errorf("#%d: state mismatch: got: %v, expected: %v", i, record.State, tc.record.State)
+		}
+		if !reflect.DeepEqual(record.Metadata, tc.record.Metadata) {
+			t.Errorf("#%d: metadata mismatch: got: %v, expected: %v", i, record.Metadata, tc.record.Metadata)
+		}
+	}
+}
+
+func TestWriteInvalidMove(t *testing.T) {
+	for i, move := range []*shogi.Move{
+		{Src: shogi.Position{File: 7, Rank: 7}, Dst: shogi.Position{File: 7, Rank: 6}},
+		{Src: shogi.Position{File: 7, Rank: 7}, Dst: shogi.Position{File: 7, Rank: 5}, Piece: shogi.BFU},
+	} {
+		record := &shogi.Record{
+			State: logic.NewInitialState(),
+			Moves: []*shogi.Move{move},
+		}
+		if _, err := csa.String(record); !errors.Is(err, shogi.ErrInvalidMove) {
+			t.Errorf("#%d: got error: %v, expected: %v", i, err, shogi.ErrInvalidMove)
+		}
+	}
+}
+
+func TestWriteNoState(t *testing.T) {
+	if _, err := csa.String(&shogi.Record{}); err != shogi.ErrNoState {
+		t.Errorf("got error: %v, expected: %v", err, shogi.ErrNoState)
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	matches, err := filepath.Glob(filepath.Join("..", "..", "testdata", "*.csa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) == 0 {
+		t.Fatal("no testdata")
+	}
+	for _, path := range matches {
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		record, err := csa.Parse(file)
+		file.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := csa.String(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := csa.ParseString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !recordEquals(record, parsed) {
+			t.Errorf("%s: round trip mismatch", path)
+		}
+	}
+}
+
+func recordEquals(a, b *shogi.Record) bool {
+	for i := 0; i < 2; i++ {
+		if (a.Players[i] == nil) != (b.Players[i] == nil) {
+			return false
+		}
+		if a.Players[i] != nil && *a.Players[i] != *b.Players[i] {
+			return false
+		}
+	}
+	if !a.State.Equals(b.State) {
+		return false
+	}
+	if !reflect.DeepEqual(a.Metadata, b.Metadata) {
+		return false
+	}
+	if a.Result != b.Result || a.Termination != b.Termination {
+		return false
+	}
+	if len(a.Moves) != len(b.Moves) {
+		return false
+	}
+	for i := range a.Moves {
+		if *a.Moves[i] != *b.Moves[i] {
+			return false
+		}
+	}
+	if len(a.MoveInfos) != len(b.MoveInfos) {
+		return false
+	}
+	for i := range a.MoveInfos {
+		if a.MoveInfos[i].Time != b.MoveInfos[i].Time {
+			return false
+		}
+	}
+	return true
+}
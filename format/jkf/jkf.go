@@ -0,0 +1,545 @@
+package jkf
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/format/kif"
+	"github.com/sugyan/shogi/logic"
+)
+
+// ErrInvalidFormat is error
+var ErrInvalidFormat = errors.New("invalid format")
+
+// JKF type is the root object of JSON Kifu Format
+type JKF struct {
+	Header  map[string]string `json:"header"`
+	Initial *Initial          `json:"initial,omitempty"`
+	Moves   []*MoveFormat     `json:"moves"`
+}
+
+// Initial type
+type Initial struct {
+	Preset string     `json:"preset"`
+	Data   *StateData `json:"data,omitempty"`
+}
+
+// StateData type
+type StateData struct {
+	Color int               `json:"color"`
+	Board [9][9]Piece       `json:"board"`
+	Hands [2]map[string]int `json:"hands"`
+}
+
+// Piece type
+type Piece struct {
+	Color *int   `json:"color,omitempty"`
+	Kind  string `json:"kind,omitempty"`
+}
+
+// MoveFormat type
+type MoveFormat struct {
+	Comments []string        `json:"comments,omitempty"`
+	Move     *Move           `json:"move,omitempty"`
+	Time     *Time           `json:"time,omitempty"`
+	Special  string          `json:"special,omitempty"`
+	Forks    [][]*MoveFormat `json:"forks,omitempty"`
+}
+
+// Move type
+type Move struct {
+	Color    int    `json:"color"`
+	From     *Place `json:"from,omitempty"`
+	To       *Place `json:"to"`
+	Piece    string `json:"piece"`
+	Same     bool   `json:"same,omitempty"`
+	Promote  *bool  `json:"promote,omitempty"`
+	Capture  string `json:"capture,omitempty"`
+	Relative string `json:"relative,omitempty"`
+}
+
+// Place type
+type Place struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Time type
+type Time struct {
+	Now   TimeFormat `json:"now"`
+	Total TimeFormat `json:"total"`
+}
+
+// TimeFormat type
+type TimeFormat struct {
+	H *int `json:"h,omitempty"`
+	M int  `json:"m"`
+	S int  `json:"s"`
+}
+
+var kinds = map[shogi.RawPiece]string{
+	shogi.FU: "FU",
+	shogi.KY: "KY",
+	shogi.KE: "KE",
+	shogi.GI: "GI",
+	shogi.KI: "KI",
+	shogi.KA: "KA",
+	shogi.HI: "HI",
+	shogi.OU: "OU",
+}
+
+var promotedKinds = map[shogi.RawPiece]string{
+	shogi.FU: "TO",
+	shogi.KY: "NY",
+	shogi.KE: "NK",
+	shogi.GI: "NG",
+	shogi.KA: "UM",
+	shogi.HI: "RY",
+}
+
+// handicap names in KIF of the presets
+var presets = []struct {
+	name     string
+	handicap string
+}{
+	{"HIRATE", "平手"},
+	{"KY", "香落ち"},
+	{"KY_R", "右香落ち"},
+	{"KA", "角落ち"},
+	{"HI", "飛車落ち"},
+	{"HIKY", "飛香落ち"},
+	{"2", "二枚落ち"},
+	{"3", "三枚落ち"},
+	{"4", "四枚落ち"},
+	{"5", "五枚落ち"},
+	{"5_L", "左五枚落ち"},
+	{"6", "六枚落ち"},
+	{"7_L", "左七枚落ち"},
+	{"7_R", "右七枚落ち"},
+	{"8", "八枚落ち"},
+	{"10", "十枚落ち"},
+}
+
+var specials = map[string]shogi.Termination{
+	"TORYO":           shogi.TerminationResign,
+	"CHUDAN":          shogi.TerminationAbort,
+	"SENNICHITE":      shogi.TerminationSennichite,
+	"TIME_UP":         shogi.TerminationTimeUp,
+	"ILLEGAL_MOVE":    shogi.TerminationIllegalMove,
+	"+ILLEGAL_ACTION": shogi.TerminationIllegalAction,
+	"-ILLEGAL_ACTION": shogi.TerminationIllegalAction,
+	"JISHOGI":         shogi.TerminationJishogi,
+	"KACHI":           shogi.TerminationDeclaration,
+	"HIKIWAKE":        shogi.TerminationDraw,
+	"MATTA":           shogi.TerminationMatta,
+	"TSUMI":           shogi.TerminationCheckmate,
+	"FUZUMI":          shogi.TerminationNoCheckmate,
+	"ERROR":           shogi.TerminationError,
+}
+
+var relatives = map[rune]string{
+	'左': "L",
+	'直': "C",
+	'右': "R",
+	'上': "U",
+	'寄': "M",
+	'引': "D",
+	'打': "H",
+}
+
+// Parse function
+func Parse(r io.Reader) (*shogi.Record, error) {
+	jkf := &JKF{}
+	if err := json.NewDecoder(r).Decode(jkf); err != nil {
+		return nil, err
+	}
+	return ToRecord(jkf)
+}
+
+// ParseString function
+func ParseString(s string) (*shogi.Record, error) {
+	return Parse(strings.NewReader(s))
+}
+
+// Write function writes the record in JKF
+func Write(w io.Writer, record *shogi.Record) error {
+	jkf, err := FromRecord(record)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(jkf)
+}
+
+// String function returns the record in JKF
+func String(record *shogi.Record) (string, error) {
+	b := &strings.Builder{}
+	if err := Write(b, record); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// ToRecord function converts JKF to the record. Branches in forks are ignored.
+func ToRecord(jkf *JKF) (*shogi.Record, error) {
+	record := &shogi.Record{
+		Players:   [2]*shogi.Player{},
+		Moves:     []*shogi.Move{},
+		MoveInfos: []*shogi.MoveInfo{},
+		Comments:  []string{},
+	}
+	for key, value := range jkf.Header {
+		kif.ParseHeader(record, key, value)
+	}
+	state, err := initialState(jkf.Initial)
+	if err != nil {
+		return nil, err
+	}
+	record.State = state
+
+	turn := state.Turn()
+	for i, mf := range jkf.Moves {
+		if mf.Move == nil {
+			if mf.Special != "" {
+				termination, ok := specials[mf.Special]
+				if !ok {
+					return nil, ErrInvalidFormat
+				}
+				record.Termination = termination
+				switch mf.Special {
+				case "+ILLEGAL_ACTION":
+					record.Result = shogi.LoseResult(shogi.TurnBlack)
+				case "-ILLEGAL_ACTION":
+					record.Result = shogi.LoseResult(shogi.TurnWhite)
+				default:
+					record.Result = termination.Result(turn)
+				}
+				break
+			}
+			// comments of the initial position
+			if i == 0 {
+				record.Comments = append(record.Comments, mf.Comments...)
+			}
+			continue
+		}
+		move, err := parseMove(mf.Move)
+		if err != nil {
+			return nil, err
+		}
+		info := &shogi.MoveInfo{Comments: []string{}}
+		info.Comments = append(info.Comments, mf.Comments...)
+		if mf.Time != nil {
+			info.Time = time.Duration(mf.Time.Now.M)*time.Minute + time.Duration(mf.Time.Now.S)*time.Second
+			if mf.Time.Now.H != nil {
+				info.Time += time.Duration(*mf.Time.Now.H) * time.Hour
+			}
+		}
+		record.Moves = append(record.Moves, move)
+		record.MoveInfos = append(record.MoveInfos, info)
+		turn = !turn
+	}
+	return record, nil
+}
+
+// FromRecord function converts the record to JKF
+func FromRecord(record *shogi.Record) (*JKF, error) {
+	if record.State == nil {
+		return nil, shogi.ErrNoState
+	}
+	if err := record.State.Clone().Move(record.Moves...); err != nil {
+		return nil, err
+	}
+	moveStrings, err := shogi.MoveStrings(record.State, record.Moves...)
+	if err != nil {
+		return nil, err
+	}
+	jkf := &JKF{
+		Header:  header(record),
+		Initial: initial(record.State),
+		Moves:   []*MoveFormat{{Comments: record.Comments}},
+	}
+	state := record.State.Clone()
+	withTime := false
+	for _, info := range record.MoveInfos {
+		if info != nil && info.Time > 0 {
+			withTime = true
+		}
+	}
+	totals := [2]time.Duration{}
+	for i, move := range record.Moves {
+		mf := &MoveFormat{}
+		m := &Move{
+			To:    &Place{X: move.Dst.File, Y: move.Dst.Rank},
+			Color: color(move.Piece.Turn()),
+		}
+		if move.Src == (shogi.Position{}) {
+			m.Piece = kind(move.Piece)
+		} else {
+			m.From = &Place{X: move.Src.File, Y: move.Src.Rank}
+			orig, err := state.GetPiece(move.Src.File, move.Src.Rank)
+			if err != nil {
+				return nil, err
+			}
+			m.Piece = kind(orig)
+			if orig != move.Piece {
+				promote := true
+				m.Promote = &promote
+			} else if strings.HasSuffix(moveStrings[i], "不成") {
+				promote := false
+				m.Promote = &promote
+			}
+			captured, err := state.GetPiece(move.Dst.File, move.Dst.Rank)
+			if err != nil {
+				return nil, err
+			}
+			if captured != shogi.EMP {
+				m.Capture = kind(captured)
+			}
+		}
+		if i > 0 && record.Moves[i-1].Dst == move.Dst {
+			m.Same = true
+		}
+		for _, r := range moveStrings[i] {
+			m.Relative += relatives[r]
+		}
+		mf.Move = m
+		if i < len(record.MoveInfos) && record.MoveInfos[i] != nil {
+			info := record.MoveInfos[i]
+			if len(info.Comments) > 0 {
+				mf.Comments = info.Comments
+			}
+			if withTime {
+				idx := color(move.Piece.Turn())
+				totals[idx] += info.Time
+				mf.Time = &Time{Now: nowFormat(info.Time), Total: totalFormat(totals[idx])}
+			}
+		}
+		if err := state.Move(move); err != nil {
+			return nil, err
+		}
+		jkf.Moves = append(jkf.Moves, mf)
+	}
+	if special := specialString(record); special != "" {
+		jkf.Moves = append(jkf.Moves, &MoveFormat{Special: special})
+	}
+	return jkf, nil
+}
+
+func header(record *shogi.Record) map[string]string {
+	h := map[string]string{}
+	for key, value := range record.Metadata.Others {
+		h[key] = value
+	}
+	if record.Players[0] != nil {
+		h["先手"] = record.Players[0].Name
+	}
+	if record.Players[1] != nil {
+		h["後手"] = record.Players[1].Name
+	}
+	metadata := record.Metadata
+	for key, value := range map[string]string{
+		"棋戦":   metadata.Event,
+		"場所":   metadata.Site,
+		"持ち時間": metadata.TimeLimit,
+		"戦型":   metadata.Opening,
+		"手合割":  metadata.Handicap,
+	} {
+		if value != "" {
+			h[key] = value
+		}
+	}
+	if !metadata.StartTime.IsZero() {
+		h["開始日時"] = metadata.StartTime.Format(shogi.TimeLayouts[0])
+	}
+	if !metadata.EndTime.IsZero() {
+		h["終了日時"] = metadata.EndTime.Format(shogi.TimeLayouts[0])
+	}
+	return h
+}
+
+func initialState(initial *Initial) (shogi.State, error) {
+	if initial == nil {
+		return logic.NewInitialState(), nil
+	}
+	if initial.Preset != "OTHER" {
+		for _, preset := range presets {
+			if preset.name == initial.Preset {
+				state, _ := kif.HandicapState(preset.handicap)
+				return state, nil
+			}
+		}
+		return nil, ErrInvalidFormat
+	}
+	if initial.Data == nil {
+		return nil, ErrInvalidFormat
+	}
+	board := [9][9]shogi.Piece{}
+	for x := 0; x < 9; x++ {
+		for y := 0; y < 9; y++ {
+			p := initial.Data.Board[x][y]
+			if p.Kind == "" {
+				continue
+			}
+			if p.Color == nil {
+				return nil, ErrInvalidFormat
+			}
+			piece, err := parsePiece(p.Kind, *p.Color)
+			if err != nil {
+				return nil, err
+			}
+			board[y][8-x] = piece
+		}
+	}
+	captured := [2]shogi.Captured{}
+	for i, hands := range initial.Data.Hands {
+		c := &captured[i]
+		for k, n := range hands {
+			switch k {
+			case "FU":
+				c.FU = n
+			case "KY":
+				c.KY = n
+			case "KE":
+				c.KE = n
+			case "GI":
+				c.GI = n
+			case "KI":
+				c.KI = n
+			case "KA":
+				c.KA = n
+			case "HI":
+				c.HI = n
+			default:
+				return nil, ErrInvalidFormat
+			}
+		}
+	}
+	turn := shogi.TurnBlack
+	if initial.Data.Color == 1 {
+		turn = shogi.TurnWhite
+	}
+	return logic.NewState(board, captured, turn), nil
+}
+
+func initial(state shogi.State) *Initial {
+	for _, preset := range presets {
+		if s, _ := kif.HandicapState(preset.handicap); state.Equals(s) {
+			return &Initial{Preset: preset.name}
+		}
+	}
+	data := &StateData{Color: color(state.Turn())}
+	for x := 0; x < 9; x++ {
+		for y := 0; y < 9; y++ {
+			piece, _ := state.GetPiece(x+1, y+1)
+			if piece == shogi.EMP {
+				continue
+			}
+			c := color(piece.Turn())
+			data.Board[x][y] = Piece{Color: &c, Kind: kind(piece)}
+		}
+	}
+	for i, turn := range []shogi.Turn{shogi.TurnBlack, shogi.TurnWhite} {
+		c := state.GetCaptured(turn)
+		data.Hands[i] = map[string]int{
+			"FU": c.FU, "KY": c.KY, "KE": c.KE, "GI": c.GI, "KI": c.KI, "KA": c.KA, "HI": c.HI,
+		}
+	}
+	return &Initial{Preset: "OTHER", Data: data}
+}
+
+func parseMove(m *Move) (*shogi.Move, error) {
+	if m.To == nil || !validPlace(m.To) {
+		return nil, ErrInvalidFormat
+	}
+	piece, err := parsePiece(m.Piece, m.Color)
+	if err != nil {
+		return nil, err
+	}
+	move := &shogi.Move{
+		Dst:   shogi.Position{File: m.To.X, Rank: m.To.Y},
+		Piece: piece,
+	}
+	if m.From != nil {
+		if !validPlace(m.From) {
+			return nil, ErrInvalidFormat
+		}
+		move.Src = shogi.Position{File: m.From.X, Rank: m.From.Y}
+		if m.Promote != nil && *m.Promote {
+			if piece.IsPromoted() {
+				return nil, ErrInvalidFormat
+			}
+			move.Piece = piece.Promote()
+		}
+	}
+	return move, nil
+}
+
+func parsePiece(k string, c int) (shogi.Piece, error) {
+	turn := shogi.TurnBlack
+	switch c {
+	case 0:
+	case 1:
+		turn = shogi.TurnWhite
+	default:
+		return shogi.EMP, ErrInvalidFormat
+	}
+	for raw, name := range kinds {
+		if name == k {
+			return shogi.MakePiece(raw, turn), nil
+		}
+	}
+	for raw, name := range promotedKinds {
+		if name == k {
+			return shogi.MakePiece(raw, turn).Promote(), nil
+		}
+	}
+	return shogi.EMP, ErrInvalidFormat
+}
+
+func validPlace(p *Place) bool {
+	return p.X >= 1 && p.X <= 9 && p.Y >= 1 && p.Y <= 9
+}
+
+func kind(p shogi.Piece) string {
+	if p.IsPromoted() {
+		return promotedKinds[p.Raw()]
+	}
+	return kinds[p.Raw()]
+}
+
+func color(turn shogi.Turn) int {
+	if turn == shogi.TurnWhite {
+		return 1
+	}
+	return 0
+}
+
+func specialString(record *shogi.Record) string {
+	for name, termination := range specials {
+		if termination != record.Termination || termination == shogi.TerminationIllegalAction {
+			continue
+		}
+		return name
+	}
+	if record.Termination == shogi.TerminationIllegalAction {
+		if record.Result == shogi.ResultBlackWin {
+			return "-ILLEGAL_ACTION"
+		}
+		return "+ILLEGAL_ACTION"
+	}
+	return ""
+}
+
+func nowFormat(d time.Duration) TimeFormat {
+	s := int(d / time.Second)
+	return TimeFormat{M: s / 60, S: s % 60}
+}
+
+func totalFormat(d time.Duration) TimeFormat {
+	s := int(d / time.Second)
+	h := s / 3600
+	return TimeFormat{H: &h, M: s / 60 % 60, S: s % 60}
+}
@@ -0,0 +1,283 @@
+package jkf_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/format/csa"
+	"github.com/sugyan/shogi/format/jkf"
+	"github.com/sugyan/shogi/logic"
+)
+
+func TestParse(t *testing.T) {
+	record, err := jkf.ParseString(`{
+  "header": {
+    "先手": "na2hiro",
+    "後手": "うひょ",
+    "開始日時": "2015/01/02 10:00:00",
+    "表題": "テスト"
+  },
+  "moves": [
+    {"comments": ["初期局面"]},
+    {"move": {"from": {"x": 7, "y": 7}, "to": {"x": 7, "y": 6}, "color": 0, "piece": "FU"}, "time": {"now": {"m": 0, "s": 12}, "total": {"h": 0, "m": 0, "s": 12}}},
+    {"move": {"from": {"x": 3, "y": 3}, "to": {"x": 3, "y": 4}, "color": 1, "piece": "FU"}, "comments": ["後手の一手"]},
+    {"move": {"from": {"x": 8, "y": 8}, "to": {"x": 2, "y": 2}, "color": 0, "piece": "KA", "capture": "KA", "promote": true},
+     "forks": [[{"move": {"from": {"x": 2, "y": 7}, "to": {"x": 2, "y": 6}, "color": 0, "piece": "FU"}}]]},
+    {"move": {"from": {"x": 3, "y": 1}, "to": {"x": 2, "y": 2}, "color": 1, "piece": "GI", "capture": "UM", "same": true}},
+    {"move": {"to": {"x": 4, "y": 5}, "color": 0, "piece": "KA"}},
+    {"special": "TORYO"}
+  ]
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Players[0].Name != "na2hiro" || record.Players[1].Name != "うひょ" {
+		t.Errorf("players got: %v, %v", record.Players[0], record.Players[1])
+	}
+	expectedMetadata := shogi.Metadata{
+		StartTime: time.Date(2015, 1, 2, 10, 0, 0, 0, time.UTC),
+		Others:    map[string]string{"表題": "テスト"},
+	}
+	if !reflect.DeepEqual(record.Metadata, expectedMetadata) {
+		t.Errorf("metadata got: %v, expected: %v", record.Metadata, expectedMetadata)
+	}
+	if !record.State.Equals(logic.NewInitialState()) {
+		t.Errorf("state got: %v", record.State)
+	}
+	if !reflect.DeepEqual(record.Comments, []string{"初期局面"}) {
+		t.Errorf("comments got: %v", record.Comments)
+	}
+	expectedMoves := []*shogi.Move{
+		{Src: shogi.Position{File: 7, Rank: 7}, Dst: shogi.Position{File: 7, Rank: 6}, Piece: shogi.BFU},
+		{Src: shogi.Position{File: 3, Rank: 3}, Dst: shogi.Position{File: 3, Rank: 4}, Piece: shogi.WFU},
+		{Src: shogi.Position{File: 8, Rank: 8}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.BUM},
+		{Src: shogi.Position{File: 3, Rank: 1}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.WGI},
+		{Src: shogi.Position{File: 0, Rank: 0}, Dst: shogi.Position{File: 4, Rank: 5}, Piece: shogi.BKA},
+	}
+	if len(record.Moves) != len(expectedMoves) {
+		t.Fatalf("got %d moves, expected: %d", len(record.Moves), len(expectedMoves))
+	}
+	for i, move := range record.Moves {
+		if *move != *expectedMoves[i] {
+			t.Errorf("#%d: got: %v, expected: %v", i, move, expectedMoves[i])
+		}
+	}
+	if record.MoveInfos[0].Time != 12*time.Second {
+		t.Errorf("time got: %v", record.MoveInfos[0].Time)
+	}
+	if !reflect.DeepEqual(record.MoveInfos[1].Comments, []string{"後手の一手"}) {
+		t.Errorf("move comments got: %v", record.MoveInfos[1].Comments)
+	}
+	if record.Result != shogi.ResultBlackWin || record.Termination != shogi.TerminationResign {
+		t.Errorf("result got: %v (%v)", record.Result, record.Termination)
+	}
+
+	for _, data := range []string{
+		`{"header": {}, "initial": {"preset": "UNKNOWN"}, "moves": [{}]}`,
+		`{"header": {}, "moves": [{}, {"move": {"to": {"x": 0, "y": 5}, "color": 0, "piece": "FU"}}]}`,
+		`{"header": {}, "moves": [{}, {"move": {"to": {"x": 5, "y": 5}, "color": 0, "piece": "XX"}}]}`,
+		`{"header": {}, "moves": [{}, {"special": "UNKNOWN"}]}`,
+	} {
+		if _, err := jkf.ParseString(data); err != jkf.ErrInvalidFormat {
+			t.Errorf("%s: got error: %v, expected: %v", data, err, jkf.ErrInvalidFormat)
+		}
+	}
+}
+
+func TestPresets(t *testing.T) {
+	for _, preset := range []string{"HIRATE", "KY", "KY_R", "KA", "HI", "HIKY", "2", "3", "4", "5", "5_L", "6", "7_L", "7_R", "8", "10"} {
+		record, err := jkf.ParseString(`{"header": {}, "initial": {"preset": "` + preset + `"}, "moves": [{}]}`)
+		if err != nil {
+			t.Fatalf("%s: %v", preset, err)
+		}
+		j, err := jkf.FromRecord(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if j.Initial.Preset != preset {
+			t.Errorf("got: %v, expected: %v", j.Initial.Preset, preset)
+		}
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	record, err := jkf.ParseString(`{"header": {"開始日時": "2019/01/02(水) 10:00", "終了日時": "2019年01月02日"}, "moves": [{}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := time.Date(2019, 1, 2, 10, 0, 0, 0, time.UTC); !record.Metadata.StartTime.Equal(expected) {
+		t.Errorf("start time got: %v, expected: %v", record.Metadata.StartTime, expected)
+	}
+	if record.Metadata.Others["終了日時"] != "2019年01月02日" {
+		t.Errorf("others got: %v", record.Metadata.Others)
+	}
+}
+
+func TestFromRecord(t *testing.T) {
+	state := logic.NewInitialState()
+	state.SetPiece(8, 2, shogi.EMP)
+	state.SetPiece(2, 2, shogi.EMP)
+	state.SetTurn(shogi.TurnWhite)
+	record := &shogi.Record{
+		Players: [2]*shogi.Player{{Name: "下手"}, {Name: "上手"}},
+		State:   state,
+		Moves: []*shogi.Move{
+			{Src: shogi.Position{File: 5, Rank: 1}, Dst: shogi.Position{File: 5, Rank: 2}, Piece: shogi.WOU},
+			{Src: shogi.Position{File: 7, Rank: 7}, Dst: shogi.Position{File: 7, Rank: 6}, Piece: shogi.BFU},
+			{Src: shogi.Position{File: 4, Rank: 1}, Dst: shogi.Position{File: 5, Rank: 1}, Piece: shogi.WKI},
+		},
+		MoveInfos: []*shogi.MoveInfo{
+			{Time: 10 * time.Second},
+			{Time: 3725 * time.Second},
+			{Time: 20 * time.Second},
+		},
+		Comments:    []string{},
+		Result:      shogi.ResultAborted,
+		Termination: shogi.TerminationAbort,
+	}
+	j, err := jkf.FromRecord(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j.Initial.Preset != "2" {
+		t.Errorf("preset got: %v", j.Initial.Preset)
+	}
+	if len(j.Moves) != 5 {
+		t.Fatalf("got %d moves, expected: 5", len(j.Moves))
+	}
+	if m := j.Moves[3].Move; m.Relative != "L" || m.Piece != "KI" || m.Color != 1 {
+		t.Errorf("move got: %v", m)
+	}
+	if total := j.Moves[3].Time.Total; *total.H != 0 || total.M != 0 || total.S != 30 {
+		t.Errorf("total time got: %v", total)
+	}
+	if now := j.Moves[2].Time.Now; now.M != 62 || now.S != 5 {
+		t.Errorf("now time got: %v", now)
+	}
+	if j.Moves[4].Special != "CHUDAN" {
+		t.Errorf("special got: %v", j.Moves[4].Special)
+	}
+
+	// custom initial position
+	state = logic.NewState(
+		[9][9]shogi.Piece{
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BTO, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WRY, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+		},
+		[2]shogi.Captured{{KI: 1, FU: 12}, {}},
+		shogi.TurnBlack,
+	)
+	record = &shogi.Record{
+		State: state,
+		Moves: []*shogi.Move{
+			{Src: shogi.Position{File: 0, Rank: 0}, Dst: shogi.Position{File: 5, Rank: 2}, Piece: shogi.BKI},
+		},
+		Result:      shogi.ResultBlackWin,
+		Termination: shogi.TerminationCheckmate,
+	}
+	s, err := jkf.String(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j = &jkf.JKF{}
+	if err := json.Unmarshal([]byte(s), j); err != nil {
+		t.Fatal(err)
+	}
+	if j.Initial.Preset != "OTHER" || *j.Initial.Data.Board[1][7].Color != 1 || j.Initial.Data.Board[1][7].Kind != "RY" {
+		t.Errorf("initial got: %v", j.Initial)
+	}
+	parsed, err := jkf.ParseString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.State.Equals(state) {
+		t.Errorf("state got: %v, expected: %v", parsed.State, state)
+	}
+	if parsed.Result != record.Result || parsed.Termination != record.Termination {
+		t.Errorf("result got: %v (%v)", parsed.Result, parsed.Termination)
+	}
+}
+
+func TestFromRecordErrors(t *testing.T) {
+	if _, err := jkf.FromRecord(&shogi.Record{}); err != shogi.ErrNoState {
+		t.Errorf("got error: %v, expected: %v", err, shogi.ErrNoState)
+	}
+	record := &shogi.Record{
+		State: logic.NewInitialState(),
+		Moves: []*shogi.Move{{Src: shogi.Position{File: 7, Rank: 7}, Dst: shogi.Position{File: 7, Rank: 5}, Piece: shogi.BFU}},
+	}
+	if _, err := jkf.FromRecord(record); err != shogi.ErrUnreachable {
+		t.Errorf("got error: %v, expected: %v", err, shogi.ErrUnreachable)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	matches, err := filepath.Glob(filepath.Join("..", "..", "testdata", "*.csa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) == 0 {
+		t.Fatal("no testdata")
+	}
+	for _, path := range matches {
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		record, err := csa.Parse(file)
+		file.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := jkf.String(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := jkf.ParseString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !recordEquals(parsed, record) {
+			t.Errorf("%s: round trip mismatch", path)
+		}
+	}
+}
+
+func recordEquals(a, b *shogi.Record) bool {
+	for i := 0; i < 2; i++ {
+		if (a.Players[i] == nil) != (b.Players[i] == nil) {
+			return false
+		}
+		if a.Players[i] != nil && *a.Players[i] != *b.Players[i] {
+			return false
+		}
+	}
+	if !a.State.Equals(b.State) || !reflect.DeepEqual(a.Metadata, b.Metadata) {
+		return false
+	}
+	if len(a.Moves) != len(b.Moves) || len(a.MoveInfos) != len(b.MoveInfos) {
+		return false
+	}
+	for i := range a.Moves {
+		if *a.Moves[i] != *b.Moves[i] {
+			return false
+		}
+		if a.MoveInfos[i].Time != b.MoveInfos[i].Time ||
+			!reflect.DeepEqual(a.MoveInfos[i].Comments, b.MoveInfos[i].Comments) {
+			return false
+		}
+	}
+	return a.Result == b.Result && a.Termination == b.Termination
+}
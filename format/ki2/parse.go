@@ -0,0 +1,187 @@
+package ki2
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/format/kif"
+)
+
+// ErrInvalidLine is error
+var ErrInvalidLine = errors.New("invalid line")
+
+// ParseError type
+type ParseError struct {
+	Line int
+	Move string
+	Err  error
+}
+
+// Error method
+func (e *ParseError) Error() string {
+	if e.Move != "" {
+		return fmt.Sprintf("line %d: %s: %v", e.Line, e.Move, e.Err)
+	}
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap method returns the underlying error such as shogi.ErrAmbiguousMove
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+var replacer = strings.NewReplacer(
+	"１", "1", "２", "2", "３", "3", "４", "4", "５", "5", "６", "6", "７", "7", "８", "8", "９", "9",
+	"同　", "同", "王", "玉", "龍", "竜", "☗", "▲", "☖", "△",
+)
+
+var resultRegexp = regexp.MustCompile(`^まで\d+手で(.*?)(?:により)?(?:(先手|後手|下手|上手)の(反則勝ち|反則負け|勝ち|負け))?$`)
+
+// terminations of the result line
+var resultTerminations = map[string]shogi.Termination{
+	"中断":   shogi.TerminationAbort,
+	"千日手":  shogi.TerminationSennichite,
+	"持将棋":  shogi.TerminationJishogi,
+	"引き分け": shogi.TerminationDraw,
+	"詰み":   shogi.TerminationCheckmate,
+	"時間切れ": shogi.TerminationTimeUp,
+	"反則":   shogi.TerminationIllegalMove,
+	"入玉宣言": shogi.TerminationDeclaration,
+}
+
+type parser struct {
+	r io.Reader
+}
+
+// Parse function parses UTF-8 encoded KI2 text
+func Parse(r io.Reader) (*shogi.Record, error) {
+	p := parser{r: r}
+	return p.parse()
+}
+
+// ParseString function
+func ParseString(s string) (*shogi.Record, error) {
+	return Parse(bytes.NewBufferString(s))
+}
+
+func isMoveLine(line string) bool {
+	return strings.HasPrefix(line, "▲") || strings.HasPrefix(line, "△") ||
+		strings.HasPrefix(line, "☗") || strings.HasPrefix(line, "☖")
+}
+
+func (p *parser) parse() (*shogi.Record, error) {
+	scanner := bufio.NewScanner(p.r)
+	// header and initial position are same as KIF
+	header := &strings.Builder{}
+	lines := []string{}
+	headerLines := 0
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) == 0 && !isMoveLine(strings.TrimSpace(line)) {
+			header.WriteString(line)
+			header.WriteRune('\n')
+			headerLines++
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	record, err := kif.ParseString(header.String())
+	if err != nil {
+		return nil, err
+	}
+	state := record.State.Clone()
+	for i, line := range lines {
+		lineNumber := headerLines + i + 1
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case len(trimmed) == 0, strings.HasPrefix(trimmed, "#"), strings.HasPrefix(trimmed, "&"):
+			continue
+		case strings.HasPrefix(trimmed, "まで"):
+			parseResult(record, state.Turn(), trimmed)
+		case strings.HasPrefix(trimmed, "*"):
+			comment := trimmed[1:]
+			info := record.MoveInfos[len(record.MoveInfos)-1]
+			info.Comments = append(info.Comments, comment)
+		case strings.HasPrefix(trimmed, "変化："):
+			// branches are not supported
+			return record, nil
+		case isMoveLine(trimmed):
+			for _, token := range splitMoves(replacer.Replace(trimmed)) {
+				var prev *shogi.Move
+				if len(record.Moves) > 0 {
+					prev = record.Moves[len(record.Moves)-1]
+				}
+				move, err := shogi.ParseMoveString(state, token, prev)
+				if err != nil {
+					return nil, &ParseError{Line: lineNumber, Move: token, Err: err}
+				}
+				if err := state.Move(move); err != nil {
+					return nil, &ParseError{Line: lineNumber, Move: token, Err: err}
+				}
+				record.Moves = append(record.Moves, move)
+				record.MoveInfos = append(record.MoveInfos, &shogi.MoveInfo{Comments: []string{}})
+			}
+		default:
+			return nil, &ParseError{Line: lineNumber, Err: ErrInvalidLine}
+		}
+	}
+	return record, nil
+}
+
+// splitMoves splits the line into moves which start with ▲ or △
+func splitMoves(line string) []string {
+	moves := []string{}
+	b := &strings.Builder{}
+	for _, r := range line {
+		if (r == '▲' || r == '△') && b.Len() > 0 {
+			moves = append(moves, strings.TrimSpace(b.String()))
+			b.Reset()
+		}
+		if r != ' ' && r != '　' && r != '\t' {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() > 0 {
+		moves = append(moves, strings.TrimSpace(b.String()))
+	}
+	return moves
+}
+
+// parseResult sets the result and termination of the record from the line such as "まで64手で先手の勝ち"
+func parseResult(record *shogi.Record, turn shogi.Turn, line string) {
+	m := resultRegexp.FindStringSubmatch(line)
+	if m == nil {
+		return
+	}
+	termination, ok := resultTerminations[m[1]]
+	if m[2] == "" {
+		if ok {
+			record.Termination = termination
+			record.Result = termination.Result(turn)
+		}
+		return
+	}
+	black := m[2] == "先手" || m[2] == "下手"
+	if black == strings.HasSuffix(m[3], "勝ち") {
+		record.Result = shogi.ResultBlackWin
+	} else {
+		record.Result = shogi.ResultWhiteWin
+	}
+	switch {
+	case strings.HasPrefix(m[3], "反則"):
+		record.Termination = shogi.TerminationIllegalMove
+	case ok:
+		record.Termination = termination
+	default:
+		record.Termination = shogi.TerminationResign
+	}
+}
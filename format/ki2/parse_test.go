@@ -0,0 +1,171 @@
+package ki2_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/format/csa"
+	"github.com/sugyan/shogi/format/ki2"
+)
+
+func TestParse(t *testing.T) {
+	record, err := ki2.ParseString(`
+開始日時：2019/01/02 10:00:00
+手合割：平手
+先手：先手太郎
+後手：後手花子
+
+▲７六歩    △３四歩    ▲２二角成  △同　銀
+*コメント
+▲４五角    △６二銀    ▲５六角    △５二金左
+まで8手で中断
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Players[0].Name != "先手太郎" || record.Players[1].Name != "後手花子" {
+		t.Errorf("players got: %v, %v", record.Players[0], record.Players[1])
+	}
+	expected := []*shogi.Move{
+		{Src: shogi.Position{File: 7, Rank: 7}, Dst: shogi.Position{File: 7, Rank: 6}, Piece: shogi.BFU},
+		{Src: shogi.Position{File: 3, Rank: 3}, Dst: shogi.Position{File: 3, Rank: 4}, Piece: shogi.WFU},
+		{Src: shogi.Position{File: 8, Rank: 8}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.BUM},
+		{Src: shogi.Position{File: 3, Rank: 1}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.WGI},
+		{Src: shogi.Position{File: 0, Rank: 0}, Dst: shogi.Position{File: 4, Rank: 5}, Piece: shogi.BKA},
+		{Src: shogi.Position{File: 7, Rank: 1}, Dst: shogi.Position{File: 6, Rank: 2}, Piece: shogi.WGI},
+		{Src: shogi.Position{File: 4, Rank: 5}, Dst: shogi.Position{File: 5, Rank: 6}, Piece: shogi.BKA},
+		{Src: shogi.Position{File: 4, Rank: 1}, Dst: shogi.Position{File: 5, Rank: 2}, Piece: shogi.WKI},
+	}
+	if !reflect.DeepEqual(record.Moves, expected) {
+		t.Errorf("moves got: %v, expected: %v", record.Moves, expected)
+	}
+	if !reflect.DeepEqual(record.MoveInfos[3].Comments, []string{"コメント"}) {
+		t.Errorf("comments got: %v", record.MoveInfos[3].Comments)
+	}
+	if record.Result != shogi.ResultAborted || record.Termination != shogi.TerminationAbort {
+		t.Errorf("result got: %v (%v)", record.Result, record.Termination)
+	}
+}
+
+func TestParseComments(t *testing.T) {
+	record, err := ki2.ParseString("▲７六歩\n*コメント1\n\t*コメント2\n　*コメント3\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"コメント1", "コメント2", "コメント3"}
+	if !reflect.DeepEqual(record.MoveInfos[0].Comments, expected) {
+		t.Errorf("comments got: %v, expected: %v", record.MoveInfos[0].Comments, expected)
+	}
+}
+
+func TestParseResult(t *testing.T) {
+	testCases := []struct {
+		data        string
+		result      shogi.Result
+		termination shogi.Termination
+	}{
+		{"▲７六歩 △３四歩\nまで2手で後手の勝ち\n", shogi.ResultWhiteWin, shogi.TerminationResign},
+		{"▲７六歩 △３四歩\nまで2手で時間切れにより先手の勝ち\n", shogi.ResultBlackWin, shogi.TerminationTimeUp},
+		{"▲７六歩 △３四歩\nまで2手で反則により先手の勝ち\n", shogi.ResultBlackWin, shogi.TerminationIllegalMove},
+		{"▲７六歩 △３四歩\nまで2手で先手の負け\n", shogi.ResultWhiteWin, shogi.TerminationResign},
+		{"▲７六歩 △３四歩 ▲２六歩\nまで3手で後手の反則負け\n", shogi.ResultBlackWin, shogi.TerminationIllegalMove},
+		{"▲７六歩 △３四歩 ▲２六歩\nまで3手で先手の反則勝ち\n", shogi.ResultBlackWin, shogi.TerminationIllegalMove},
+		{"▲７六歩 △３四歩 ▲２六歩\nまで3手で先手の反則負け\n", shogi.ResultWhiteWin, shogi.TerminationIllegalMove},
+		{"▲７六歩 △３四歩 ▲２六歩\nまで3手で後手の反則勝ち\n", shogi.ResultWhiteWin, shogi.TerminationIllegalMove},
+		{"▲７六歩 △３四歩\nまで2手で千日手\n", shogi.ResultDraw, shogi.TerminationSennichite},
+		{"▲７六歩 △３四歩\nまで2手で持将棋\n", shogi.ResultDraw, shogi.TerminationJishogi},
+		{"▲７六歩 △３四歩\nまで2手で引き分け\n", shogi.ResultDraw, shogi.TerminationDraw},
+		{"▲７六歩 △３四歩\nまで2手で詰み\n", shogi.ResultWhiteWin, shogi.TerminationCheckmate},
+		{"▲７六歩 △３四歩\nまで2手で不明\n", shogi.ResultNone, shogi.TerminationNone},
+		{"手合割：香落ち\n△３四歩 ▲７六歩\nまで2手で下手の勝ち\n", shogi.ResultBlackWin, shogi.TerminationResign},
+	}
+	for i, tc := range testCases {
+		record, err := ki2.ParseString(tc.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record.Result != tc.result || record.Termination != tc.termination {
+			t.Errorf("#%d: got: %v (%v), expected: %v (%v)", i, record.Result, record.Termination, tc.result, tc.termination)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := []struct {
+		data     string
+		expected *ki2.ParseError
+	}{
+		{
+			"手合割：平手\n▲７六歩 △３四歩\n▲７五角\n",
+			&ki2.ParseError{Line: 3, Move: "▲7五角", Err: shogi.ErrInvalidMove},
+		},
+		{
+			"手合割：平手\n▲７六歩 ▲２六歩\n",
+			&ki2.ParseError{Line: 2, Move: "▲2六歩", Err: shogi.ErrWrongTurn},
+		},
+		{
+			"手合割：平手\n▲７六歩 △３四歩 ▲５八金\n",
+			&ki2.ParseError{Line: 2, Move: "▲5八金", Err: shogi.ErrAmbiguousMove},
+		},
+		{
+			"手合割：平手\n▲７六歩\nあいうえお\n",
+			&ki2.ParseError{Line: 3, Err: ki2.ErrInvalidLine},
+		},
+	}
+	for i, tc := range testCases {
+		_, err := ki2.ParseString(tc.data)
+		if !reflect.DeepEqual(err, tc.expected) {
+			t.Errorf("#%d: got %v, expected: %v", i, err, tc.expected)
+		}
+		if !errors.Is(err, tc.expected.Err) {
+			t.Errorf("#%d: %v should be %v", i, err, tc.expected.Err)
+		}
+	}
+}
+
+func TestParseRecords(t *testing.T) {
+	replacer := strings.NewReplacer(
+		"1", "１", "2", "２", "3", "３", "4", "４", "5", "５", "6", "６", "7", "７", "8", "８", "9", "９", "同", "同　",
+	)
+	matches, err := filepath.Glob(filepath.Join("..", "..", "testdata", "*.csa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, match := range matches {
+		file, err := os.Open(match)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		record, err := csa.Parse(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		notations, err := shogi.MoveStrings(record.State, record.Moves...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := &strings.Builder{}
+		b.WriteString("手合割：平手\n")
+		for j, notation := range notations {
+			b.WriteString(replacer.Replace(notation))
+			if j%6 == 5 {
+				b.WriteRune('\n')
+			} else {
+				b.WriteRune(' ')
+			}
+		}
+		result, err := ki2.ParseString(b.String())
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if !reflect.DeepEqual(result.Moves, record.Moves) {
+			t.Errorf("#%d: moves got: %v, expected: %v", i, result.Moves, record.Moves)
+		}
+	}
+}
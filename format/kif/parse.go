@@ -0,0 +1,423 @@
+package kif
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/format/bod"
+	"github.com/sugyan/shogi/logic"
+)
+
+// ErrInvalidLine is error
+var ErrInvalidLine = errors.New("invalid line")
+
+var (
+	moveRegexp   = regexp.MustCompile(`^\s*(\d+)\s+(\S.*)$`)
+	sourceRegexp = regexp.MustCompile(`\(([1-9])([1-9])\)$`)
+	timeRegexp   = regexp.MustCompile(`\(\s*(\d+):(\d+)(?:/\s*\d+:\d+:\d+)?\s*\)$`)
+)
+
+// black pieces of the move notation, longer names first
+var pieceNames = []struct {
+	name  string
+	piece shogi.Piece
+}{
+	{"成香", shogi.BNY},
+	{"成桂", shogi.BNK},
+	{"成銀", shogi.BNG},
+	{"歩", shogi.BFU},
+	{"香", shogi.BKY},
+	{"桂", shogi.BKE},
+	{"銀", shogi.BGI},
+	{"金", shogi.BKI},
+	{"角", shogi.BKA},
+	{"飛", shogi.BHI},
+	{"玉", shogi.BOU},
+	{"王", shogi.BOU},
+	{"と", shogi.BTO},
+	{"杏", shogi.BNY},
+	{"圭", shogi.BNK},
+	{"全", shogi.BNG},
+	{"馬", shogi.BUM},
+	{"龍", shogi.BRY},
+	{"竜", shogi.BRY},
+}
+
+// squares of the white pieces removed from the initial position
+var handicaps = map[string][]shogi.Position{
+	"平手":    {},
+	"香落ち":   {{File: 1, Rank: 1}},
+	"右香落ち":  {{File: 9, Rank: 1}},
+	"角落ち":   {{File: 2, Rank: 2}},
+	"飛車落ち":  {{File: 8, Rank: 2}},
+	"飛香落ち":  {{File: 8, Rank: 2}, {File: 1, Rank: 1}},
+	"二枚落ち":  {{File: 8, Rank: 2}, {File: 2, Rank: 2}},
+	"三枚落ち":  {{File: 8, Rank: 2}, {File: 2, Rank: 2}, {File: 1, Rank: 1}},
+	"四枚落ち":  {{File: 8, Rank: 2}, {File: 2, Rank: 2}, {File: 1, Rank: 1}, {File: 9, Rank: 1}},
+	"五枚落ち":  {{File: 8, Rank: 2}, {File: 2, Rank: 2}, {File: 1, Rank: 1}, {File: 9, Rank: 1}, {File: 2, Rank: 1}},
+	"左五枚落ち": {{File: 8, Rank: 2}, {File: 2, Rank: 2}, {File: 1, Rank: 1}, {File: 9, Rank: 1}, {File: 8, Rank: 1}},
+	"六枚落ち":  {{File: 8, Rank: 2}, {File: 2, Rank: 2}, {File: 1, Rank: 1}, {File: 9, Rank: 1}, {File: 2, Rank: 1}, {File: 8, Rank: 1}},
+	"七枚落ち": {{File: 8, Rank: 2}, {File: 2, Rank: 2}, {File: 1, Rank: 1}, {File: 9, Rank: 1}, {File: 2, Rank: 1}, {File: 8, Rank: 1},
+		{File: 3, Rank: 1}},
+	"左七枚落ち": {{File: 8, Rank: 2}, {File: 2, Rank: 2}, {File: 1, Rank: 1}, {File: 9, Rank: 1}, {File: 2, Rank: 1}, {File: 8, Rank: 1},
+		{File: 3, Rank: 1}},
+	"右七枚落ち": {{File: 8, Rank: 2}, {File: 2, Rank: 2}, {File: 1, Rank: 1}, {File: 9, Rank: 1}, {File: 2, Rank: 1}, {File: 8, Rank: 1},
+		{File: 7, Rank: 1}},
+	"八枚落ち": {{File: 8, Rank: 2}, {File: 2, Rank: 2}, {File: 1, Rank: 1}, {File: 9, Rank: 1}, {File: 2, Rank: 1}, {File: 8, Rank: 1},
+		{File: 3, Rank: 1}, {File: 7, Rank: 1}},
+	"十枚落ち": {{File: 8, Rank: 2}, {File: 2, Rank: 2}, {File: 1, Rank: 1}, {File: 9, Rank: 1}, {File: 2, Rank: 1}, {File: 8, Rank: 1},
+		{File: 3, Rank: 1}, {File: 7, Rank: 1}, {File: 4, Rank: 1}, {File: 6, Rank: 1}},
+}
+
+// special moves which end the game
+var specialMoves = map[string]shogi.Termination{
+	"投了":   shogi.TerminationResign,
+	"中断":   shogi.TerminationAbort,
+	"千日手":  shogi.TerminationSennichite,
+	"持将棋":  shogi.TerminationJishogi,
+	"詰み":   shogi.TerminationCheckmate,
+	"切れ負け": shogi.TerminationTimeUp,
+	"反則勝ち": shogi.TerminationIllegalMove,
+	"反則負け": shogi.TerminationIllegalMove,
+	"入玉勝ち": shogi.TerminationDeclaration,
+	"不戦勝":  shogi.TerminationForfeit,
+	"不戦敗":  shogi.TerminationForfeit,
+	"不詰":   shogi.TerminationNoCheckmate,
+}
+
+type parser struct {
+	r io.Reader
+}
+
+// Parse function parses UTF-8 encoded KIF text
+func Parse(r io.Reader) (*shogi.Record, error) {
+	p := parser{r: r}
+	return p.parse()
+}
+
+// ParseString function
+func ParseString(s string) (*shogi.Record, error) {
+	return Parse(bytes.NewBufferString(s))
+}
+
+type diagram struct {
+	exist    bool
+	board    [9][9]shogi.Piece
+	captured [2]shogi.Captured
+	turn     shogi.Turn
+}
+
+func (p *parser) parse() (*shogi.Record, error) {
+	record := &shogi.Record{
+		Players:   [2]*shogi.Player{},
+		Moves:     []*shogi.Move{},
+		MoveInfos: []*shogi.MoveInfo{},
+		Comments:  []string{},
+	}
+	b := &diagram{}
+	rows := 0
+	ended := false
+	scanner := bufio.NewScanner(p.r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+		if len(trimmed) == 0 {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "#"), strings.HasPrefix(line, "&"):
+			continue
+		case strings.HasPrefix(line, "*"): // comment
+			comment := line[1:]
+			if len(record.MoveInfos) == 0 {
+				record.Comments = append(record.Comments, comment)
+			} else {
+				info := record.MoveInfos[len(record.MoveInfos)-1]
+				info.Comments = append(info.Comments, comment)
+			}
+		case strings.HasPrefix(line, "変化："):
+			// branches are not supported
+			return p.finish(record, b)
+		case strings.HasPrefix(trimmed, "まで"), strings.HasPrefix(trimmed, "手数＝"):
+			continue
+		case strings.HasPrefix(trimmed, "手数-"):
+			if record.State == nil {
+				state, err := initialState(record, b)
+				if err != nil {
+					return nil, err
+				}
+				record.State = state
+			}
+		case strings.HasPrefix(trimmed, "９ ８ ７") || strings.HasPrefix(line, "+-"):
+			b.exist = true
+		case strings.HasPrefix(line, "|"):
+			b.exist = true
+			if rows > 8 {
+				return nil, ErrInvalidLine
+			}
+			row, err := bod.ParseRow(line)
+			if err != nil {
+				return nil, ErrInvalidLine
+			}
+			b.board[rows] = row
+			rows++
+		case trimmed == "先手番" || trimmed == "下手番":
+			b.turn = shogi.TurnBlack
+		case trimmed == "後手番" || trimmed == "上手番":
+			b.exist = true
+			b.turn = shogi.TurnWhite
+		case moveRegexp.MatchString(line):
+			if ended {
+				continue
+			}
+			if record.State == nil {
+				state, err := initialState(record, b)
+				if err != nil {
+					return nil, err
+				}
+				record.State = state
+			}
+			m := moveRegexp.FindStringSubmatch(line)
+			body := strings.TrimSuffix(strings.TrimSpace(m[2]), "+")
+			info := &shogi.MoveInfo{Comments: []string{}}
+			if loc := timeRegexp.FindStringSubmatchIndex(body); loc != nil {
+				minutes, _ := strconv.Atoi(body[loc[2]:loc[3]])
+				seconds, _ := strconv.Atoi(body[loc[4]:loc[5]])
+				info.Time = time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+				body = strings.TrimSpace(body[:loc[0]])
+			}
+			turn := record.State.Turn()
+			if len(record.Moves)%2 == 1 {
+				turn = !turn
+			}
+			if termination, ok := specialMoves[body]; ok {
+				record.Termination = termination
+				switch body {
+				case "反則勝ち", "不戦勝":
+					record.Result = shogi.WinResult(turn)
+				case "不戦敗":
+					record.Result = shogi.LoseResult(turn)
+				default:
+					record.Result = termination.Result(turn)
+				}
+				ended = true
+				continue
+			}
+			var prev *shogi.Move
+			if len(record.Moves) > 0 {
+				prev = record.Moves[len(record.Moves)-1]
+			}
+			move, err := parseMove(body, turn, prev)
+			if err != nil {
+				return nil, err
+			}
+			record.Moves = append(record.Moves, move)
+			record.MoveInfos = append(record.MoveInfos, info)
+		case strings.ContainsRune(line, '：'):
+			idx := strings.IndexRune(line, '：')
+			key, value := strings.TrimSpace(line[:idx]), strings.TrimSpace(line[idx+len("："):])
+			if err := parseHeader(record, b, key, value); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, ErrInvalidLine
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return p.finish(record, b)
+}
+
+func (p *parser) finish(record *shogi.Record, b *diagram) (*shogi.Record, error) {
+	if record.State == nil {
+		state, err := initialState(record, b)
+		if err != nil {
+			return nil, err
+		}
+		record.State = state
+	}
+	return record, nil
+}
+
+func initialState(record *shogi.Record, b *diagram) (shogi.State, error) {
+	if b.exist {
+		return logic.NewState(b.board, b.captured, b.turn), nil
+	}
+	state, ok := HandicapState(record.Metadata.Handicap)
+	if !ok {
+		// unknown handicap requires the board diagram
+		return nil, ErrInvalidLine
+	}
+	return state, nil
+}
+
+// HandicapState function returns the initial state of the handicap such as "香落ち", or false if the handicap is unknown
+func HandicapState(handicap string) (*logic.State, bool) {
+	state := logic.NewInitialState()
+	if handicap == "" {
+		return state, true
+	}
+	positions, ok := handicaps[handicap]
+	if !ok {
+		return state, false
+	}
+	if len(positions) > 0 {
+		for _, position := range positions {
+			state.SetPiece(position.File, position.Rank, shogi.EMP)
+		}
+		state.SetTurn(shogi.TurnWhite)
+	}
+	return state, true
+}
+
+func parseHeader(record *shogi.Record, b *diagram, key, value string) error {
+	switch key {
+	case "先手の持駒", "下手の持駒":
+		b.exist = true
+		return parseCaptured(&b.captured[0], value)
+	case "後手の持駒", "上手の持駒":
+		b.exist = true
+		return parseCaptured(&b.captured[1], value)
+	}
+	ParseHeader(record, key, value)
+	return nil
+}
+
+// ParseHeader function sets the value of the KIF header such as "先手" or "開始日時" to the record
+func ParseHeader(record *shogi.Record, key, value string) {
+	switch key {
+	case "先手", "下手":
+		record.Players[0] = &shogi.Player{Name: value}
+	case "後手", "上手":
+		record.Players[1] = &shogi.Player{Name: value}
+	case "開始日時":
+		t, err := shogi.ParseTime(value)
+		if err != nil {
+			// keep the value of the unknown format as is
+			setOther(&record.Metadata, key, value)
+			return
+		}
+		record.Metadata.StartTime = t
+	case "終了日時":
+		t, err := shogi.ParseTime(value)
+		if err != nil {
+			setOther(&record.Metadata, key, value)
+			return
+		}
+		record.Metadata.EndTime = t
+	case "棋戦":
+		record.Metadata.Event = value
+	case "場所":
+		record.Metadata.Site = value
+	case "戦型":
+		record.Metadata.Opening = value
+	case "手合割":
+		record.Metadata.Handicap = value
+	case "持ち時間":
+		record.Metadata.TimeLimit = value
+	default:
+		setOther(&record.Metadata, key, value)
+	}
+}
+
+func setOther(metadata *shogi.Metadata, key, value string) {
+	if metadata.Others == nil {
+		metadata.Others = map[string]string{}
+	}
+	metadata.Others[key] = value
+}
+
+func parseMove(body string, turn shogi.Turn, prev *shogi.Move) (*shogi.Move, error) {
+	move := &shogi.Move{}
+	// source
+	if m := sourceRegexp.FindStringSubmatch(body); m != nil {
+		move.Src = shogi.Position{File: int(m[1][0] - '0'), Rank: int(m[2][0] - '0')}
+		body = strings.TrimSpace(body[:len(body)-len(m[0])])
+	}
+	// destination
+	runes := []rune(body)
+	if len(runes) < 2 {
+		return nil, ErrInvalidLine
+	}
+	if runes[0] == '同' {
+		if prev == nil {
+			return nil, ErrInvalidLine
+		}
+		move.Dst = prev.Dst
+		runes = []rune(strings.TrimLeft(string(runes[1:]), " 　"))
+	} else {
+		file, rank := parseNumber(runes[0]), parseNumber(runes[1])
+		if file < 1 || rank < 1 {
+			return nil, ErrInvalidLine
+		}
+		move.Dst = shogi.Position{File: file, Rank: rank}
+		runes = runes[2:]
+	}
+	// piece
+	rest := string(runes)
+	var piece shogi.Piece
+	for _, e := range pieceNames {
+		if strings.HasPrefix(rest, e.name) {
+			piece = e.piece
+			rest = rest[len(e.name):]
+			break
+		}
+	}
+	if piece == shogi.EMP {
+		return nil, ErrInvalidLine
+	}
+	move.Piece = shogi.MakePiece(piece.Raw(), turn)
+	if piece.IsPromoted() {
+		move.Piece = move.Piece.Promote()
+	}
+	switch rest {
+	case "":
+	case "成":
+		move.Piece = move.Piece.Promote()
+	case "不成":
+	case "打":
+		if move.Src != (shogi.Position{File: 0, Rank: 0}) {
+			return nil, ErrInvalidLine
+		}
+		return move, nil
+	default:
+		return nil, ErrInvalidLine
+	}
+	if move.Src == (shogi.Position{File: 0, Rank: 0}) {
+		return nil, ErrInvalidLine
+	}
+	return move, nil
+}
+
+// parseNumber returns the number of a digit or a kanji numeral, or -1
+func parseNumber(r rune) int {
+	switch {
+	case r >= '1' && r <= '9':
+		return int(r - '0')
+	case r >= '１' && r <= '９':
+		return int(r-'１') + 1
+	}
+	for i, c := range []rune("〇一二三四五六七八九") {
+		if c == r {
+			return i
+		}
+	}
+	return -1
+}
+
+func parseCaptured(c *shogi.Captured, s string) error {
+	captured, err := bod.ParseCaptured(s)
+	if err != nil {
+		return ErrInvalidLine
+	}
+	*c = captured
+	return nil
+}
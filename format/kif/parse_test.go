@@ -0,0 +1,271 @@
+package kif_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/format/kif"
+	"github.com/sugyan/shogi/logic"
+)
+
+func TestParse(t *testing.T) {
+	record, err := kif.ParseString(`
+# ---- Kifu for Windows V7 V7.30 棋譜ファイル ----
+開始日時：2019/01/02(水) 10:00:00
+終了日時：2019/01/02(水) 10:30:15
+棋戦：テスト
+場所：東京
+戦型：横歩取り
+表題：棋聖戦
+手合割：平手　　
+先手：先手太郎
+後手：後手花子
+持ち時間：各10分
+*対局前コメント
+手数----指手---------消費時間--
+   1 ７六歩(77)   ( 0:01/00:00:01)
+*初手コメント
+*二行目
+   2 ３四歩(33)   ( 0:02/00:00:02)
+   3 ２二角成(88)   ( 0:03/00:00:04)
+   4 同　銀(31)   ( 1:04/00:01:06)
+   5 ４五角打   ( 0:05/00:00:09)
+   6 投了   ( 0:06/00:01:12)
+まで5手で先手の勝ち
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Players[0].Name != "先手太郎" || record.Players[1].Name != "後手花子" {
+		t.Errorf("players got: %v, %v", record.Players[0], record.Players[1])
+	}
+	if !record.State.Equals(logic.NewInitialState()) {
+		t.Errorf("state got: %v", record.State)
+	}
+	expectedMetadata := shogi.Metadata{
+		Event:     "テスト",
+		Site:      "東京",
+		StartTime: time.Date(2019, 1, 2, 10, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2019, 1, 2, 10, 30, 15, 0, time.UTC),
+		TimeLimit: "各10分",
+		Opening:   "横歩取り",
+		Handicap:  "平手",
+		Others:    map[string]string{"表題": "棋聖戦"},
+	}
+	if !reflect.DeepEqual(record.Metadata, expectedMetadata) {
+		t.Errorf("metadata got: %v, expected: %v", record.Metadata, expectedMetadata)
+	}
+	if !reflect.DeepEqual(record.Comments, []string{"対局前コメント"}) {
+		t.Errorf("comments got: %v", record.Comments)
+	}
+	if record.Result != shogi.ResultBlackWin || record.Termination != shogi.TerminationResign {
+		t.Errorf("result got: %v (%v)", record.Result, record.Termination)
+	}
+	expectedMoves := []*shogi.Move{
+		{Src: shogi.Position{File: 7, Rank: 7}, Dst: shogi.Position{File: 7, Rank: 6}, Piece: shogi.BFU},
+		{Src: shogi.Position{File: 3, Rank: 3}, Dst: shogi.Position{File: 3, Rank: 4}, Piece: shogi.WFU},
+		{Src: shogi.Position{File: 8, Rank: 8}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.BUM},
+		{Src: shogi.Position{File: 3, Rank: 1}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.WGI},
+		{Src: shogi.Position{File: 0, Rank: 0}, Dst: shogi.Position{File: 4, Rank: 5}, Piece: shogi.BKA},
+	}
+	if !reflect.DeepEqual(record.Moves, expectedMoves) {
+		t.Errorf("moves got: %v, expected: %v", record.Moves, expectedMoves)
+	}
+	expectedInfos := []*shogi.MoveInfo{
+		{Time: time.Second, Comments: []string{"初手コメント", "二行目"}},
+		{Time: 2 * time.Second, Comments: []string{}},
+		{Time: 3 * time.Second, Comments: []string{}},
+		{Time: time.Minute + 4*time.Second, Comments: []string{}},
+		{Time: 5 * time.Second, Comments: []string{}},
+	}
+	if !reflect.DeepEqual(record.MoveInfos, expectedInfos) {
+		t.Errorf("move infos got: %v, expected: %v", record.MoveInfos, expectedInfos)
+	}
+}
+
+func TestParseUnknownTime(t *testing.T) {
+	record, err := kif.ParseString("開始日時：2019年01月01日\n終了日時：不明\n手数----指手---------消費時間--\n   1 ７六歩(77)\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !record.Metadata.StartTime.IsZero() || !record.Metadata.EndTime.IsZero() {
+		t.Errorf("time got: %v, %v", record.Metadata.StartTime, record.Metadata.EndTime)
+	}
+	expected := map[string]string{"開始日時": "2019年01月01日", "終了日時": "不明"}
+	if !reflect.DeepEqual(record.Metadata.Others, expected) {
+		t.Errorf("others got: %v, expected: %v", record.Metadata.Others, expected)
+	}
+	if len(record.Moves) != 1 {
+		t.Errorf("got %d moves, expected: 1", len(record.Moves))
+	}
+}
+
+func TestParseBoard(t *testing.T) {
+	testCases := []struct {
+		data  string
+		state shogi.State
+		moves []*shogi.Move
+	}{
+		{
+			data: `
+後手の持駒：なし
+  ９ ８ ７ ６ ５ ４ ３ ２ １
++---------------------------+
+| ・ ・ ・ ・ ・ ・ ・v桂v香|一
+| ・ ・ ・ ・ ・ ・v金v玉 ・|二
+| ・ ・ ・ ・ ・ ・ ・ 歩 ・|三
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|四
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|五
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|六
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|七
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|八
+| ・ ・ ・ ・ 玉 ・ ・ ・v龍|九
++---------------------------+
+先手の持駒：金　歩十二　
+先手番
+手数----指手---------消費時間--
+   1 １二金打
+`,
+			state: logic.NewState(
+				[9][9]shogi.Piece{
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WKE, shogi.WKY},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WKI, shogi.WOU, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BFU, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WRY},
+				},
+				[2]shogi.Captured{{FU: 12, KI: 1}, {}},
+				shogi.TurnBlack,
+			),
+			moves: []*shogi.Move{
+				{Src: shogi.Position{File: 0, Rank: 0}, Dst: shogi.Position{File: 1, Rank: 2}, Piece: shogi.BKI},
+			},
+		},
+		{
+			data: `
+手合割：香落ち
+上手：上手
+下手：下手
+手数----指手---------消費時間--
+   1 ３四歩(33)
+   2 ７六歩(77)
+   3 ８八角不成(22)
+`,
+			state: logic.NewState(
+				[9][9]shogi.Piece{
+					{shogi.WKY, shogi.WKE, shogi.WGI, shogi.WKI, shogi.WOU, shogi.WKI, shogi.WGI, shogi.WKE, shogi.EMP},
+					{shogi.EMP, shogi.WHI, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WKA, shogi.EMP},
+					{shogi.WFU, shogi.WFU, shogi.WFU, shogi.WFU, shogi.WFU, shogi.WFU, shogi.WFU, shogi.WFU, shogi.WFU},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.BFU, shogi.BFU, shogi.BFU, shogi.BFU, shogi.BFU, shogi.BFU, shogi.BFU, shogi.BFU, shogi.BFU},
+					{shogi.EMP, shogi.BKA, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BHI, shogi.EMP},
+					{shogi.BKY, shogi.BKE, shogi.BGI, shogi.BKI, shogi.BOU, shogi.BKI, shogi.BGI, shogi.BKE, shogi.BKY},
+				},
+				[2]shogi.Captured{},
+				shogi.TurnWhite,
+			),
+			moves: []*shogi.Move{
+				{Src: shogi.Position{File: 3, Rank: 3}, Dst: shogi.Position{File: 3, Rank: 4}, Piece: shogi.WFU},
+				{Src: shogi.Position{File: 7, Rank: 7}, Dst: shogi.Position{File: 7, Rank: 6}, Piece: shogi.BFU},
+				{Src: shogi.Position{File: 2, Rank: 2}, Dst: shogi.Position{File: 8, Rank: 8}, Piece: shogi.WKA},
+			},
+		},
+	}
+	for i, tc := range testCases {
+		record, err := kif.ParseString(tc.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !record.State.Equals(tc.state) {
+			t.Errorf("#%d: state got: %v, expected: %v", i, record.State, tc.state)
+		}
+		if !reflect.DeepEqual(record.Moves, tc.moves) {
+			t.Errorf("#%d: moves got: %v, expected: %v", i, record.Moves, tc.moves)
+		}
+	}
+}
+
+func TestParseHandicaps(t *testing.T) {
+	testCases := []struct {
+		handicap string
+		removed  []shogi.Position
+	}{
+		{"左五枚落ち", []shogi.Position{{File: 8, Rank: 2}, {File: 2, Rank: 2}, {File: 1, Rank: 1}, {File: 9, Rank: 1}, {File: 8, Rank: 1}}},
+		{"左七枚落ち", []shogi.Position{{File: 8, Rank: 2}, {File: 2, Rank: 2}, {File: 1, Rank: 1}, {File: 9, Rank: 1}, {File: 2, Rank: 1},
+			{File: 8, Rank: 1}, {File: 3, Rank: 1}}},
+		{"右七枚落ち", []shogi.Position{{File: 8, Rank: 2}, {File: 2, Rank: 2}, {File: 1, Rank: 1}, {File: 9, Rank: 1}, {File: 2, Rank: 1},
+			{File: 8, Rank: 1}, {File: 7, Rank: 1}}},
+	}
+	for i, tc := range testCases {
+		record, err := kif.ParseString("手合割：" + tc.handicap + "\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := logic.NewInitialState()
+		for _, p := range tc.removed {
+			expected.SetPiece(p.File, p.Rank, shogi.EMP)
+		}
+		expected.SetTurn(shogi.TurnWhite)
+		if !record.State.Equals(expected) {
+			t.Errorf("#%d: got: %v, expected: %v", i, record.State, expected)
+		}
+	}
+
+	// unknown handicap with the board diagram
+	record, err := kif.ParseString(`手合割：その他
+後手の持駒：なし
+  ９ ８ ７ ６ ５ ４ ３ ２ １
++---------------------------+
+| ・ ・ ・ ・v玉 ・ ・ ・ ・|一
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|二
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|三
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|四
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|五
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|六
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|七
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|八
+| ・ ・ ・ ・ 玉 ・ ・ ・ ・|九
++---------------------------+
+先手の持駒：なし
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if piece, _ := record.State.GetPiece(5, 1); piece != shogi.WOU {
+		t.Errorf("got: %v, expected: %v", piece, shogi.WOU)
+	}
+	s, err := kif.String(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := kif.ParseString(s); err != nil {
+		t.Errorf("round trip got error: %v", err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for i, data := range []string{
+		"手数----指手---------消費時間--\n   1 同　歩(77)\n",
+		"手数----指手---------消費時間--\n   1 ７六歩\n",
+		"手数----指手---------消費時間--\n   1 ７六歩打(77)\n",
+		"手数----指手---------消費時間--\n   1 ７六象(77)\n",
+		"手数----指手---------消費時間--\n   1 ７六歩左(77)\n",
+		"| ・ ・ ・|一\n",
+		"先手の持駒：玉\n",
+		"不明な行\n",
+		"手合割：不明\n",
+		"手合割：その他\n手数----指手---------消費時間--\n   1 ７六歩(77)\n",
+	} {
+		if _, err := kif.ParseString(data); err != kif.ErrInvalidLine {
+			t.Errorf("#%d: got %v, expected: %v", i, err, kif.ErrInvalidLine)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package kif
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/format/bod"
+)
+
+var terminationNames = map[shogi.Termination]string{
+	shogi.TerminationResign:      "投了",
+	shogi.TerminationAbort:       "中断",
+	shogi.TerminationSennichite:  "千日手",
+	shogi.TerminationJishogi:     "持将棋",
+	shogi.TerminationCheckmate:   "詰み",
+	shogi.TerminationTimeUp:      "切れ負け",
+	shogi.TerminationDeclaration: "入玉勝ち",
+	shogi.TerminationNoCheckmate: "不詰",
+}
+
+// Write function writes the record in KIF format
+func Write(w io.Writer, record *shogi.Record) error {
+	if record.State == nil {
+		return shogi.ErrNoState
+	}
+	if err := record.State.Clone().Move(record.Moves...); err != nil {
+		return err
+	}
+	moveStrings, err := shogi.MoveStrings(record.State, record.Moves...)
+	if err != nil {
+		return err
+	}
+	handicapped := false
+	if positions, ok := handicaps[record.Metadata.Handicap]; ok && len(positions) > 0 {
+		handicapped = true
+	}
+	names := [2]string{"先手", "後手"}
+	if handicapped {
+		names = [2]string{"下手", "上手"}
+	}
+
+	bw := bufio.NewWriter(w)
+	// header
+	metadata := record.Metadata
+	if !metadata.StartTime.IsZero() {
+		fmt.Fprintf(bw, "開始日時：%s\n", metadata.StartTime.Format(shogi.TimeLayouts[0]))
+	}
+	if !metadata.EndTime.IsZero() {
+		fmt.Fprintf(bw, "終了日時：%s\n", metadata.EndTime.Format(shogi.TimeLayouts[0]))
+	}
+	if metadata.Event != "" {
+		fmt.Fprintf(bw, "棋戦：%s\n", metadata.Event)
+	}
+	if metadata.Site != "" {
+		fmt.Fprintf(bw, "場所：%s\n", metadata.Site)
+	}
+	if metadata.TimeLimit != "" {
+		fmt.Fprintf(bw, "持ち時間：%s\n", metadata.TimeLimit)
+	}
+	if metadata.Opening != "" {
+		fmt.Fprintf(bw, "戦型：%s\n", metadata.Opening)
+	}
+	keys := make([]string, 0, len(metadata.Others))
+	for key := range metadata.Others {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(bw, "%s：%s\n", key, metadata.Others[key])
+	}
+	if metadata.Handicap != "" {
+		fmt.Fprintf(bw, "手合割：%s\n", metadata.Handicap)
+	}
+	if state, ok := HandicapState(metadata.Handicap); !ok || !record.State.Equals(state) {
+		if err := bod.WriteWithNames(bw, record.State, names); err != nil {
+			return err
+		}
+	}
+	for i, player := range record.Players {
+		if player != nil {
+			fmt.Fprintf(bw, "%s：%s\n", names[i], player.Name)
+		}
+	}
+	for _, comment := range record.Comments {
+		fmt.Fprintf(bw, "*%s\n", comment)
+	}
+	// moves
+	bw.WriteString("手数----指手---------消費時間--\n")
+	withTime := false
+	for _, info := range record.MoveInfos {
+		if info != nil && info.Time > 0 {
+			withTime = true
+		}
+	}
+	totals := [2]time.Duration{}
+	for i, move := range record.Moves {
+		body := moveBody(moveStrings[i], move)
+		var info *shogi.MoveInfo
+		if i < len(record.MoveInfos) {
+			info = record.MoveInfos[i]
+		}
+		if withTime && info != nil {
+			idx := 0
+			if move.Piece.Turn() == shogi.TurnWhite {
+				idx = 1
+			}
+			totals[idx] += info.Time
+			fmt.Fprintf(bw, "%4d %s   %s\n", i+1, pad(body, 14), timeString(info.Time, totals[idx]))
+		} else {
+			fmt.Fprintf(bw, "%4d %s\n", i+1, body)
+		}
+		if info != nil {
+			for _, comment := range info.Comments {
+				fmt.Fprintf(bw, "*%s\n", comment)
+			}
+		}
+	}
+	// result
+	turn := record.State.Turn()
+	if len(record.Moves)%2 == 1 {
+		turn = !turn
+	}
+	if name := terminationName(record, turn); name != "" {
+		fmt.Fprintf(bw, "%4d %s\n", len(record.Moves)+1, name)
+	}
+	switch record.Result {
+	case shogi.ResultBlackWin:
+		fmt.Fprintf(bw, "まで%d手で%sの勝ち\n", len(record.Moves), names[0])
+	case shogi.ResultWhiteWin:
+		fmt.Fprintf(bw, "まで%d手で%sの勝ち\n", len(record.Moves), names[1])
+	case shogi.ResultDraw:
+		switch record.Termination {
+		case shogi.TerminationSennichite, shogi.TerminationJishogi:
+			fmt.Fprintf(bw, "まで%d手で%s\n", len(record.Moves), terminationNames[record.Termination])
+		default:
+			fmt.Fprintf(bw, "まで%d手で引き分け\n", len(record.Moves))
+		}
+	case shogi.ResultAborted:
+		fmt.Fprintf(bw, "まで%d手で中断\n", len(record.Moves))
+	}
+	return bw.Flush()
+}
+
+// String function returns the record in KIF format
+func String(record *shogi.Record) (string, error) {
+	b := &bytes.Buffer{}
+	if err := Write(b, record); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// terminationName returns the special move name of the termination by the side to move
+func terminationName(record *shogi.Record, turn shogi.Turn) string {
+	win := record.Result == shogi.WinResult(turn)
+	switch record.Termination {
+	case shogi.TerminationIllegalMove, shogi.TerminationIllegalAction:
+		if win {
+			return "反則勝ち"
+		}
+		return "反則負け"
+	case shogi.TerminationForfeit:
+		if win {
+			return "不戦勝"
+		}
+		return "不戦敗"
+	}
+	return terminationNames[record.Termination]
+}
+
+// moveBody converts the move notation such as "▲同銀右" to "同　銀(31)"
+func moveBody(s string, move *shogi.Move) string {
+	b := &strings.Builder{}
+	for i, r := range []rune(s) {
+		switch {
+		case i == 0 && (r == '▲' || r == '△'):
+		case strings.ContainsRune("右左直上引寄打", r):
+		case r >= '1' && r <= '9':
+			b.WriteRune(r - '1' + '１')
+		case r == '同':
+			b.WriteString("同　")
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if move.Src == (shogi.Position{}) {
+		b.WriteRune('打')
+	} else {
+		fmt.Fprintf(b, "(%d%d)", move.Src.File, move.Src.Rank)
+	}
+	return b.String()
+}
+
+// pad appends spaces so that the string fills the width, counting non-ASCII characters as two columns
+func pad(s string, width int) string {
+	w := 0
+	for _, r := range s {
+		if r < 0x80 {
+			w++
+		} else {
+			w += 2
+		}
+	}
+	if w >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-w)
+}
+
+func timeString(d, total time.Duration) string {
+	d, total = d/time.Second, total/time.Second
+	return fmt.Sprintf("(%2d:%02d/%02d:%02d:%02d)", d/60, d%60, total/3600, total/60%60, total%60)
+}
@@ -0,0 +1,207 @@
+package kif_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/format/csa"
+	"github.com/sugyan/shogi/format/kif"
+	"github.com/sugyan/shogi/logic"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		record   *shogi.Record
+		expected string
+	}{
+		{
+			&shogi.Record{
+				Players: [2]*shogi.Player{{Name: "先手太郎"}, {Name: "後手花子"}},
+				State:   logic.NewInitialState(),
+				Moves: []*shogi.Move{
+					{Src: shogi.Position{File: 7, Rank: 7}, Dst: shogi.Position{File: 7, Rank: 6}, Piece: shogi.BFU},
+					{Src: shogi.Position{File: 3, Rank: 3}, Dst: shogi.Position{File: 3, Rank: 4}, Piece: shogi.WFU},
+					{Src: shogi.Position{File: 8, Rank: 8}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.BUM},
+					{Src: shogi.Position{File: 3, Rank: 1}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.WGI},
+					{Src: shogi.Position{File: 0, Rank: 0}, Dst: shogi.Position{File: 4, Rank: 5}, Piece: shogi.BKA},
+				},
+				MoveInfos: []*shogi.MoveInfo{
+					{Time: 1 * time.Second, Comments: []string{"初手コメント"}},
+					{Time: 2 * time.Second},
+					{Time: 3 * time.Second},
+					{Time: 64 * time.Second},
+					{Time: 5 * time.Second},
+				},
+				Metadata: shogi.Metadata{
+					StartTime: time.Date(2019, 1, 2, 10, 0, 0, 0, time.UTC),
+					Event:     "テスト",
+				},
+				Comments:    []string{"対局前コメント"},
+				Result:      shogi.ResultBlackWin,
+				Termination: shogi.TerminationResign,
+			},
+			`開始日時：2019/01/02 10:00:00
+棋戦：テスト
+先手：先手太郎
+後手：後手花子
+*対局前コメント
+手数----指手---------消費時間--
+   1 ７六歩(77)       ( 0:01/00:00:01)
+*初手コメント
+   2 ３四歩(33)       ( 0:02/00:00:02)
+   3 ２二角成(88)     ( 0:03/00:00:04)
+   4 同　銀(31)       ( 1:04/00:01:06)
+   5 ４五角打         ( 0:05/00:00:09)
+   6 投了
+まで5手で先手の勝ち
+`,
+		},
+		{
+			&shogi.Record{
+				State: logic.NewState(
+					[9][9]shogi.Piece{
+						{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+						{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+						{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BTO, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+						{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+						{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+						{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+						{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+						{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WRY, shogi.EMP},
+						{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					},
+					[2]shogi.Captured{
+						{KI: 1, FU: 12},
+						{},
+					},
+					shogi.TurnBlack,
+				),
+				Moves: []*shogi.Move{
+					{Src: shogi.Position{File: 0, Rank: 0}, Dst: shogi.Position{File: 5, Rank: 2}, Piece: shogi.BKI},
+				},
+				Result:      shogi.ResultBlackWin,
+				Termination: shogi.TerminationCheckmate,
+			},
+			`後手の持駒：なし
+  ９ ８ ７ ６ ５ ４ ３ ２ １
++---------------------------+
+| ・ ・ ・ ・v玉 ・ ・ ・ ・|一
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|二
+| ・ ・ ・ ・ と ・ ・ ・ ・|三
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|四
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|五
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|六
+| ・ ・ ・ ・ ・ ・ ・ ・ ・|七
+| ・ ・ ・ ・ ・ ・ ・v龍 ・|八
+| ・ ・ ・ ・ 玉 ・ ・ ・ ・|九
++---------------------------+
+先手の持駒：金　歩十二
+先手番
+手数----指手---------消費時間--
+   1 ５二金打
+   2 詰み
+まで1手で先手の勝ち
+`,
+		},
+	}
+	for i, tc := range tests {
+		s, err := kif.String(tc.record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != tc.expected {
+			t.Errorf("#%d: got: %v, expected: %v", i, s, tc.expected)
+		}
+		record, err := kif.ParseString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !recordEquals(record, tc.record) {
+			t.Errorf("#%d: round trip mismatch: %v", i, record)
+		}
+	}
+}
+
+func TestWriteErrors(t *testing.T) {
+	if _, err := kif.String(&shogi.Record{}); err != shogi.ErrNoState {
+		t.Errorf("got error: %v, expected: %v", err, shogi.ErrNoState)
+	}
+	record := &shogi.Record{
+		State: logic.NewInitialState(),
+		Moves: []*shogi.Move{{Src: shogi.Position{File: 7, Rank: 7}, Dst: shogi.Position{File: 7, Rank: 5}, Piece: shogi.BFU}},
+	}
+	if _, err := kif.String(record); err != shogi.ErrUnreachable {
+		t.Errorf("got error: %v, expected: %v", err, shogi.ErrUnreachable)
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	matches, err := filepath.Glob(filepath.Join("..", "..", "testdata", "*.csa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) == 0 {
+		t.Fatal("no testdata")
+	}
+	for _, path := range matches {
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		record, err := csa.Parse(file)
+		file.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := kif.String(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := kif.ParseString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !recordEquals(parsed, record) {
+			t.Errorf("%s: round trip mismatch", path)
+		}
+	}
+}
+
+func recordEquals(a, b *shogi.Record) bool {
+	for i := 0; i < 2; i++ {
+		if (a.Players[i] == nil) != (b.Players[i] == nil) {
+			return false
+		}
+		if a.Players[i] != nil && *a.Players[i] != *b.Players[i] {
+			return false
+		}
+	}
+	if !a.State.Equals(b.State) {
+		return false
+	}
+	if len(a.Moves) != len(b.Moves) {
+		return false
+	}
+	for i := range a.Moves {
+		if *a.Moves[i] != *b.Moves[i] {
+			return false
+		}
+	}
+	for i := range a.MoveInfos {
+		if i >= len(b.MoveInfos) {
+			break
+		}
+		if a.MoveInfos[i].Time != b.MoveInfos[i].Time {
+			return false
+		}
+		if len(a.MoveInfos[i].Comments)+len(b.MoveInfos[i].Comments) > 0 &&
+			!reflect.DeepEqual(a.MoveInfos[i].Comments, b.MoveInfos[i].Comments) {
+			return false
+		}
+	}
+	return a.Result == b.Result && a.Termination == b.Termination
+}
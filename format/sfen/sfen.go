@@ -0,0 +1,218 @@
+package sfen
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/logic"
+)
+
+// ErrInvalidFormat is error
+var ErrInvalidFormat = errors.New("invalid sfen")
+
+var pieceMap = map[string]shogi.Piece{
+	"P": shogi.BFU, "p": shogi.WFU,
+	"L": shogi.BKY, "l": shogi.WKY,
+	"N": shogi.BKE, "n": shogi.WKE,
+	"S": shogi.BGI, "s": shogi.WGI,
+	"G": shogi.BKI, "g": shogi.WKI,
+	"B": shogi.BKA, "b": shogi.WKA,
+	"R": shogi.BHI, "r": shogi.WHI,
+	"K": shogi.BOU, "k": shogi.WOU,
+	"+P": shogi.BTO, "+p": shogi.WTO,
+	"+L": shogi.BNY, "+l": shogi.WNY,
+	"+N": shogi.BNK, "+n": shogi.WNK,
+	"+S": shogi.BNG, "+s": shogi.WNG,
+	"+B": shogi.BUM, "+b": shogi.WUM,
+	"+R": shogi.BRY, "+r": shogi.WRY,
+}
+
+var pieceStringMap = map[shogi.Piece]string{}
+
+func init() {
+	for s, p := range pieceMap {
+		pieceStringMap[p] = s
+	}
+}
+
+// Parse function parses the SFEN string, and returns the state and the move number
+func Parse(s string) (*logic.State, int, error) {
+	fields := strings.Fields(s)
+	if len(fields) > 0 && fields[0] == "sfen" {
+		fields = fields[1:]
+	}
+	if len(fields) == 1 && fields[0] == "startpos" {
+		return logic.NewInitialState(), 1, nil
+	}
+	if len(fields) != 3 && len(fields) != 4 {
+		return nil, 0, ErrInvalidFormat
+	}
+	// board
+	board := [9][9]shogi.Piece{}
+	rows := strings.Split(fields[0], "/")
+	if len(rows) != 9 {
+		return nil, 0, ErrInvalidFormat
+	}
+	for i, row := range rows {
+		j := 0
+		promoted := false
+		for _, c := range row {
+			switch {
+			case c >= '1' && c <= '9' && !promoted:
+				j += int(c - '0')
+			case c == '+' && !promoted:
+				promoted = true
+			default:
+				key := string(c)
+				if promoted {
+					key = "+" + key
+				}
+				piece, ok := pieceMap[key]
+				if !ok || j > 8 {
+					return nil, 0, ErrInvalidFormat
+				}
+				board[i][j] = piece
+				j++
+				promoted = false
+			}
+			if j > 9 {
+				return nil, 0, ErrInvalidFormat
+			}
+		}
+		if j != 9 || promoted {
+			return nil, 0, ErrInvalidFormat
+		}
+	}
+	// turn
+	var turn shogi.Turn
+	switch fields[1] {
+	case "b":
+		turn = shogi.TurnBlack
+	case "w":
+		turn = shogi.TurnWhite
+	default:
+		return nil, 0, ErrInvalidFormat
+	}
+	// captured pieces
+	captured := [2]shogi.Captured{}
+	if fields[2] != "-" {
+		n := 0
+		for _, c := range fields[2] {
+			if c >= '0' && c <= '9' {
+				n = n*10 + int(c-'0')
+				continue
+			}
+			piece, ok := pieceMap[string(c)]
+			if !ok || piece.Raw() == shogi.OU {
+				return nil, 0, ErrInvalidFormat
+			}
+			if n == 0 {
+				n = 1
+			}
+			idx := 0
+			if piece.Turn() == shogi.TurnWhite {
+				idx = 1
+			}
+			hand := &captured[idx]
+			switch piece.Raw() {
+			case shogi.FU:
+				hand.FU += n
+			case shogi.KY:
+				hand.KY += n
+			case shogi.KE:
+				hand.KE += n
+			case shogi.GI:
+				hand.GI += n
+			case shogi.KI:
+				hand.KI += n
+			case shogi.KA:
+				hand.KA += n
+			case shogi.HI:
+				hand.HI += n
+			}
+			n = 0
+		}
+		if n != 0 {
+			return nil, 0, ErrInvalidFormat
+		}
+	}
+	// move number
+	moveNumber := 1
+	if len(fields) == 4 {
+		num, err := strconv.Atoi(fields[3])
+		if err != nil {
+			return nil, 0, ErrInvalidFormat
+		}
+		moveNumber = num
+	}
+	return logic.NewState(board, captured, turn), moveNumber, nil
+}
+
+// String function returns the SFEN string of the state
+func String(state shogi.State, moveNumber int) string {
+	b := &strings.Builder{}
+	// board
+	for rank := 1; rank <= 9; rank++ {
+		if rank > 1 {
+			b.WriteRune('/')
+		}
+		empty := 0
+		for file := 9; file >= 1; file-- {
+			piece, _ := state.GetPiece(file, rank)
+			if piece == shogi.EMP {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				b.WriteString(strconv.Itoa(empty))
+				empty = 0
+			}
+			b.WriteString(pieceStringMap[piece])
+		}
+		if empty > 0 {
+			b.WriteString(strconv.Itoa(empty))
+		}
+	}
+	// turn
+	switch state.Turn() {
+	case shogi.TurnBlack:
+		b.WriteString(" b ")
+	case shogi.TurnWhite:
+		b.WriteString(" w ")
+	}
+	// captured pieces
+	hand := &strings.Builder{}
+	for _, turn := range []shogi.Turn{shogi.TurnBlack, shogi.TurnWhite} {
+		c := state.GetCaptured(turn)
+		for _, e := range []struct {
+			raw shogi.RawPiece
+			num int
+		}{
+			{shogi.HI, c.HI},
+			{shogi.KA, c.KA},
+			{shogi.KI, c.KI},
+			{shogi.GI, c.GI},
+			{shogi.KE, c.KE},
+			{shogi.KY, c.KY},
+			{shogi.FU, c.FU},
+		} {
+			if e.num > 1 {
+				hand.WriteString(strconv.Itoa(e.num))
+			}
+			if e.num > 0 {
+				hand.WriteString(pieceStringMap[shogi.MakePiece(e.raw, turn)])
+			}
+		}
+	}
+	if hand.Len() == 0 {
+		b.WriteRune('-')
+	} else {
+		b.WriteString(hand.String())
+	}
+	// move number
+	b.WriteRune(' ')
+	b.WriteString(strconv.Itoa(moveNumber))
+	return b.String()
+}
@@ -0,0 +1,123 @@
+package sfen_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/format/csa"
+	"github.com/sugyan/shogi/format/sfen"
+	"github.com/sugyan/shogi/logic"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		data       string
+		state      *logic.State
+		moveNumber int
+	}{
+		{
+			data:       "lnsgkgsnl/1r5b1/ppppppppp/9/9/9/PPPPPPPPP/1B5R1/LNSGKGSNL b - 1",
+			state:      logic.NewInitialState(),
+			moveNumber: 1,
+		},
+		{
+			data:       "startpos",
+			state:      logic.NewInitialState(),
+			moveNumber: 1,
+		},
+		{
+			data: "sfen 8l/1l+R2P3/p2pBG1pp/kps1p4/Nn1P2G2/P1P1P2PP/1PS6/1KSG3+r1/LN2+p3L w Sbgn3p 124",
+			state: logic.NewState(
+				[9][9]shogi.Piece{
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WKY},
+					{shogi.EMP, shogi.WKY, shogi.BRY, shogi.EMP, shogi.EMP, shogi.BFU, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.WFU, shogi.EMP, shogi.EMP, shogi.WFU, shogi.BKA, shogi.BKI, shogi.EMP, shogi.WFU, shogi.WFU},
+					{shogi.WOU, shogi.WFU, shogi.WGI, shogi.EMP, shogi.WFU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.BKE, shogi.WKE, shogi.EMP, shogi.BFU, shogi.EMP, shogi.EMP, shogi.BKI, shogi.EMP, shogi.EMP},
+					{shogi.BFU, shogi.EMP, shogi.BFU, shogi.EMP, shogi.BFU, shogi.EMP, shogi.EMP, shogi.BFU, shogi.BFU},
+					{shogi.EMP, shogi.BFU, shogi.BGI, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.BOU, shogi.BGI, shogi.BKI, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WRY, shogi.EMP},
+					{shogi.BKY, shogi.BKE, shogi.EMP, shogi.EMP, shogi.WTO, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BKY},
+				},
+				[2]shogi.Captured{
+					{GI: 1},
+					{FU: 3, KE: 1, KI: 1, KA: 1},
+				},
+				shogi.TurnWhite,
+			),
+			moveNumber: 124,
+		},
+	}
+	for i, tc := range testCases {
+		state, moveNumber, err := sfen.Parse(tc.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !state.Equals(tc.state) {
+			t.Errorf("#%d: state got: %v, expected: %v", i, state, tc.state)
+		}
+		if moveNumber != tc.moveNumber {
+			t.Errorf("#%d: move number got: %d, expected: %d", i, moveNumber, tc.moveNumber)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for i, data := range []string{
+		"",
+		"lnsgkgsnl/1r5b1/ppppppppp/9/9/9/PPPPPPPPP/1B5R1/LNSGKGSNL",
+		"lnsgkgsnl/1r5b1/ppppppppp/9/9/9/PPPPPPPPP/1B5R1 b - 1",
+		"lnsgkgsnl/1r5b1/ppppppppp/9/9/9/PPPPPPPPP/1B5R1/LNSGKGSN b - 1",
+		"lnsgkgsnl/1r5b1/ppppppppp/9/9/9/PPPPPPPPP/1B5R1/LNSGKGSNLL b - 1",
+		"lnsgkgsnl/1r5b1/ppppppppp/9/9/9/PPPPPPPPP/1B5R1/LNSGKGSN+ b - 1",
+		"lnsgkgsnl/1r5b1/ppppppppp/9/9/9/PPPPPPPPP/1B5R1/LNSGKGSNX b - 1",
+		"lnsgkgsnl/1r5b1/ppppppppp/9/9/9/PPPPPPPPP/1B5R1/LNSGKGSNL x - 1",
+		"lnsgkgsnl/1r5b1/ppppppppp/9/9/9/PPPPPPPPP/1B5R1/LNSGKGSNL b K 1",
+		"lnsgkgsnl/1r5b1/ppppppppp/9/9/9/PPPPPPPPP/1B5R1/LNSGKGSNL b 2 1",
+		"lnsgkgsnl/1r5b1/ppppppppp/9/9/9/PPPPPPPPP/1B5R1/LNSGKGSNL b - x",
+	} {
+		if _, _, err := sfen.Parse(data); err != sfen.ErrInvalidFormat {
+			t.Errorf("#%d: got %v, expected: %v", i, err, sfen.ErrInvalidFormat)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	matches, err := filepath.Glob(filepath.Join("..", "..", "testdata", "*.csa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, match := range matches {
+		file, err := os.Open(match)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		record, err := csa.Parse(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := record.State
+		for j, move := range record.Moves {
+			if err := s.Move(move); err != nil {
+				t.Fatal(err)
+			}
+			str := sfen.String(s, j+2)
+			state, moveNumber, err := sfen.Parse(str)
+			if err != nil {
+				t.Fatalf("#%d-%d: %v", i, j, err)
+			}
+			if !state.Equals(s) || moveNumber != j+2 {
+				t.Errorf("#%d-%d: got: %v, expected: %v", i, j, state, s)
+			}
+			if result := sfen.String(state, moveNumber); result != str {
+				t.Errorf("#%d-%d: got: %s, expected: %s", i, j, result, str)
+			}
+		}
+	}
+	if result := sfen.String(logic.NewInitialState(), 1); result != "lnsgkgsnl/1r5b1/ppppppppp/9/9/9/PPPPPPPPP/1B5R1/LNSGKGSNL b - 1" {
+		t.Errorf("got: %s", result)
+	}
+}
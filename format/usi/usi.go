@@ -0,0 +1,133 @@
+package usi
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/sugyan/shogi"
+)
+
+// ErrInvalidFormat is error
+var ErrInvalidFormat = errors.New("invalid usi move")
+
+var pieceMap = map[byte]shogi.RawPiece{
+	'P': shogi.FU,
+	'L': shogi.KY,
+	'N': shogi.KE,
+	'S': shogi.GI,
+	'G': shogi.KI,
+	'B': shogi.KA,
+	'R': shogi.HI,
+}
+
+var pieceStringMap = map[shogi.RawPiece]byte{}
+
+func init() {
+	for c, raw := range pieceMap {
+		pieceStringMap[raw] = c
+	}
+}
+
+// MoveString function returns the USI notation of the move in the state
+func MoveString(state shogi.State, move *shogi.Move) (string, error) {
+	b := &strings.Builder{}
+	if move.Src == (shogi.Position{File: 0, Rank: 0}) {
+		c, ok := pieceStringMap[move.Piece.Raw()]
+		if !ok || move.Piece.IsPromoted() {
+			return "", shogi.ErrInvalidMove
+		}
+		b.WriteByte(c)
+		b.WriteByte('*')
+		if err := writeSquare(b, move.Dst); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	}
+	orig, err := state.GetPiece(move.Src.File, move.Src.Rank)
+	if err != nil {
+		return "", err
+	}
+	if orig.Raw() != move.Piece.Raw() {
+		return "", shogi.ErrInvalidMove
+	}
+	if err := writeSquare(b, move.Src); err != nil {
+		return "", err
+	}
+	if err := writeSquare(b, move.Dst); err != nil {
+		return "", err
+	}
+	if orig != move.Piece {
+		b.WriteByte('+')
+	}
+	return b.String(), nil
+}
+
+// ParseMove function parses the USI notation of the move in the state
+func ParseMove(state shogi.State, s string) (*shogi.Move, error) {
+	var move *shogi.Move
+	switch {
+	case len(s) == 4 && s[1] == '*':
+		raw, ok := pieceMap[s[0]]
+		if !ok {
+			return nil, ErrInvalidFormat
+		}
+		dst, err := parseSquare(s[2:4])
+		if err != nil {
+			return nil, err
+		}
+		move = &shogi.Move{
+			Src:   shogi.Position{File: 0, Rank: 0},
+			Dst:   dst,
+			Piece: shogi.MakePiece(raw, state.Turn()),
+		}
+	case len(s) == 4 || (len(s) == 5 && s[4] == '+'):
+		src, err := parseSquare(s[0:2])
+		if err != nil {
+			return nil, err
+		}
+		dst, err := parseSquare(s[2:4])
+		if err != nil {
+			return nil, err
+		}
+		piece, err := state.GetPiece(src.File, src.Rank)
+		if err != nil {
+			return nil, err
+		}
+		if piece == shogi.EMP || piece.Turn() != state.Turn() {
+			return nil, shogi.ErrPieceNotFound
+		}
+		if len(s) == 5 {
+			if piece.IsPromoted() {
+				return nil, shogi.ErrInvalidPromotion
+			}
+			piece = piece.Promote()
+		}
+		move = &shogi.Move{
+			Src:   src,
+			Dst:   dst,
+			Piece: piece,
+		}
+	default:
+		return nil, ErrInvalidFormat
+	}
+	if err := state.Clone().Move(move); err != nil {
+		return nil, err
+	}
+	return move, nil
+}
+
+func writeSquare(b *strings.Builder, p shogi.Position) error {
+	if p.File < 1 || p.File > 9 || p.Rank < 1 || p.Rank > 9 {
+		return shogi.ErrInvalidPosition
+	}
+	b.WriteByte(byte('0' + p.File))
+	b.WriteByte(byte('a' + p.Rank - 1))
+	return nil
+}
+
+func parseSquare(s string) (shogi.Position, error) {
+	if s[0] < '1' || s[0] > '9' || s[1] < 'a' || s[1] > 'i' {
+		return shogi.Position{}, ErrInvalidFormat
+	}
+	return shogi.Position{File: int(s[0] - '0'), Rank: int(s[1]-'a') + 1}, nil
+}
@@ -0,0 +1,139 @@
+package usi_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/format/csa"
+	"github.com/sugyan/shogi/format/sfen"
+	"github.com/sugyan/shogi/format/usi"
+)
+
+func TestMoveString(t *testing.T) {
+	state, _, err := sfen.Parse("lnsgkgsnl/1r5b1/pppppp1pp/6p2/9/2P6/PP1PPPPPP/1B5R1/LNSGKGSNL b - 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		move     *shogi.Move
+		expected string
+	}{
+		{
+			&shogi.Move{Src: shogi.Position{File: 2, Rank: 7}, Dst: shogi.Position{File: 2, Rank: 6}, Piece: shogi.BFU},
+			"2g2f",
+		},
+		{
+			&shogi.Move{Src: shogi.Position{File: 8, Rank: 8}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.BUM},
+			"8h2b+",
+		},
+		{
+			&shogi.Move{Src: shogi.Position{File: 8, Rank: 8}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.BKA},
+			"8h2b",
+		},
+	}
+	for i, tc := range testCases {
+		result, err := usi.MoveString(state, tc.move)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != tc.expected {
+			t.Errorf("#%d: got: %s, expected: %s", i, result, tc.expected)
+		}
+		move, err := usi.ParseMove(state, tc.expected)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *move != *tc.move {
+			t.Errorf("#%d: got: %v, expected: %v", i, move, tc.move)
+		}
+	}
+	// drop
+	if err := state.Move(
+		&shogi.Move{Src: shogi.Position{File: 8, Rank: 8}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.BUM},
+		&shogi.Move{Src: shogi.Position{File: 3, Rank: 1}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.WGI},
+	); err != nil {
+		t.Fatal(err)
+	}
+	drop := &shogi.Move{Src: shogi.Position{File: 0, Rank: 0}, Dst: shogi.Position{File: 5, Rank: 5}, Piece: shogi.BKA}
+	if result, err := usi.MoveString(state, drop); err != nil || result != "B*5e" {
+		t.Errorf("got: %s, %v, expected: %s", result, err, "B*5e")
+	}
+	if move, err := usi.ParseMove(state, "B*5e"); err != nil || *move != *drop {
+		t.Errorf("got: %v, %v, expected: %v", move, err, drop)
+	}
+}
+
+func TestParseMoveErrors(t *testing.T) {
+	state, _, err := sfen.Parse("startpos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		data     string
+		expected error
+	}{
+		{"", usi.ErrInvalidFormat},
+		{"7g7", usi.ErrInvalidFormat},
+		{"7g7f=", usi.ErrInvalidFormat},
+		{"7j7f", usi.ErrInvalidFormat},
+		{"K*5e", usi.ErrInvalidFormat},
+		{"P*5e", shogi.ErrNotInHand},
+		{"5e5d", shogi.ErrPieceNotFound},
+		{"3c3d", shogi.ErrPieceNotFound},
+		{"7g7e", shogi.ErrUnreachable},
+		{"7g7f+", shogi.ErrInvalidPromotion},
+	}
+	for i, tc := range testCases {
+		if _, err := usi.ParseMove(state, tc.data); err != tc.expected {
+			t.Errorf("#%d: got %v, expected: %v", i, err, tc.expected)
+		}
+	}
+
+	state, _, err = sfen.Parse("lnsgkgsnl/1r5+B1/pppppp1pp/6p2/9/2P6/PP1PPPPPP/7R1/LNSGKGSNL b b 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := usi.ParseMove(state, "2b3a"); err != nil {
+		t.Errorf("got %v, expected: %v", err, nil)
+	}
+	if _, err := usi.ParseMove(state, "2b3a+"); err != shogi.ErrInvalidPromotion {
+		t.Errorf("got %v, expected: %v", err, shogi.ErrInvalidPromotion)
+	}
+}
+
+func TestRecords(t *testing.T) {
+	matches, err := filepath.Glob(filepath.Join("..", "..", "testdata", "*.csa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, match := range matches {
+		file, err := os.Open(match)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		record, err := csa.Parse(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := record.State
+		for j, move := range record.Moves {
+			str, err := usi.MoveString(s, move)
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsed, err := usi.ParseMove(s, str)
+			if err != nil {
+				t.Fatalf("#%d-%d: %s: %v", i, j, str, err)
+			}
+			if *parsed != *move {
+				t.Errorf("#%d-%d: got: %v, expected: %v", i, j, parsed, move)
+			}
+			if err := s.Move(move); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
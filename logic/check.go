@@ -0,0 +1,98 @@
+package logic
+
+import (
+	"github.com/sugyan/shogi"
+)
+
+// InCheck method for shogi.CheckState interface
+func (s *State) InCheck() bool {
+	return s.isChecked(s.turn)
+}
+
+// Checkers method for shogi.CheckState interface
+func (s *State) Checkers() []shogi.Position {
+	i, j, ok := s.kingSquare(s.turn)
+	if !ok {
+		return []shogi.Position{}
+	}
+	return s.attackers(i, j, !s.turn)
+}
+
+// Attackers method returns the positions of the pieces of turn which attack the square
+func (s *State) Attackers(file, rank int, turn shogi.Turn) ([]shogi.Position, error) {
+	if file < 1 || file > 9 || rank < 1 || rank > 9 {
+		return nil, shogi.ErrInvalidPosition
+	}
+	return s.attackers(rank-1, 9-file, turn), nil
+}
+
+// isChecked reports whether the king of turn is attacked
+func (s *State) isChecked(turn shogi.Turn) bool {
+	i, j, ok := s.kingSquare(turn)
+	if !ok {
+		return false
+	}
+	return len(s.attackers(i, j, !turn)) > 0
+}
+
+func (s *State) kingSquare(turn shogi.Turn) (int, int, bool) {
+	king := shogi.MakePiece(shogi.OU, turn)
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			if s.board[i][j] == king {
+				return i, j, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// attackers returns the positions of the pieces of turn which attack board[i][j]
+func (s *State) attackers(i, j int, turn shogi.Turn) []shogi.Position {
+	positions := []shogi.Position{}
+	for ii := 0; ii < 9; ii++ {
+		for jj := 0; jj < 9; jj++ {
+			p := s.board[ii][jj]
+			if p != shogi.EMP && p.Turn() == turn && s.reaches(ii, jj, i, j) {
+				positions = append(positions, shogi.Position{File: 9 - jj, Rank: ii + 1})
+			}
+		}
+	}
+	return positions
+}
+
+// reaches reports whether the piece on board[i][j] can move to board[ii][jj]
+func (s *State) reaches(i, j, ii, jj int) bool {
+	p := s.board[i][j]
+	for _, d := range reachableMap[p] {
+		if i+d.i == ii && j+d.j == jj {
+			return true
+		}
+	}
+	for _, d := range stepMap[p] {
+		for k, l := i+d.i, j+d.j; k >= 0 && k < 9 && l >= 0 && l < 9; k, l = k+d.i, l+d.j {
+			if k == ii && l == jj {
+				return true
+			}
+			if s.board[k][l] != shogi.EMP {
+				break
+			}
+		}
+	}
+	return false
+}
+
+// IsCheckmate method for shogi.CheckState interface
+func (s *State) IsCheckmate() bool {
+	return s.isChecked(s.turn) && !s.HasLegalMoves()
+}
+
+// HasLegalMoves method reports whether the side to move has any legal move
+func (s *State) HasLegalMoves() bool {
+	for _, move := range s.candidateMoves() {
+		if !s.isSelfCheck(move) && !s.IsUchifuzume(move) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package logic_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/logic"
+)
+
+func TestCheckers(t *testing.T) {
+	testCases := []struct {
+		state    shogi.CheckState
+		expected []shogi.Position
+	}{
+		{
+			state:    logic.NewInitialState(),
+			expected: []shogi.Position{},
+		},
+		{
+			state: logic.NewState(
+				[9][9]shogi.Piece{
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WHI, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WOU},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.WKA, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WKE, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+				},
+				[2]shogi.Captured{},
+				shogi.TurnBlack,
+			),
+			expected: []shogi.Position{{File: 5, Rank: 1}, {File: 4, Rank: 7}},
+		},
+		{
+			state: logic.NewState(
+				[9][9]shogi.Piece{
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BKY, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BUM},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+				},
+				[2]shogi.Captured{},
+				shogi.TurnWhite,
+			),
+			expected: []shogi.Position{{File: 5, Rank: 5}},
+		},
+	}
+	for i, tc := range testCases {
+		checkers := tc.state.Checkers()
+		if !reflect.DeepEqual(checkers, tc.expected) {
+			t.Errorf("#%d: checkers got: %v, expected: %v", i, checkers, tc.expected)
+		}
+		if tc.state.InCheck() != (len(tc.expected) > 0) {
+			t.Errorf("#%d: in check got: %v, expected: %v", i, tc.state.InCheck(), len(tc.expected) > 0)
+		}
+	}
+}
+
+func TestAttackers(t *testing.T) {
+	s := logic.NewInitialState()
+	testCases := []struct {
+		file, rank int
+		turn       shogi.Turn
+		expected   []shogi.Position
+	}{
+		{5, 8, shogi.TurnBlack, []shogi.Position{{File: 2, Rank: 8}, {File: 6, Rank: 9}, {File: 5, Rank: 9}, {File: 4, Rank: 9}}},
+		{5, 8, shogi.TurnWhite, []shogi.Position{}},
+		{7, 7, shogi.TurnBlack, []shogi.Position{{File: 8, Rank: 8}, {File: 8, Rank: 9}}},
+		{2, 4, shogi.TurnBlack, []shogi.Position{}},
+		{2, 6, shogi.TurnBlack, []shogi.Position{{File: 2, Rank: 7}}},
+		{8, 2, shogi.TurnWhite, []shogi.Position{{File: 7, Rank: 1}}},
+	}
+	for i, tc := range testCases {
+		attackers, err := s.Attackers(tc.file, tc.rank, tc.turn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(attackers, tc.expected) {
+			t.Errorf("#%d: attackers got: %v, expected: %v", i, attackers, tc.expected)
+		}
+	}
+	if _, err := s.Attackers(0, 5, shogi.TurnBlack); err != shogi.ErrInvalidPosition {
+		t.Errorf("got %v, expected: %v", err, shogi.ErrInvalidPosition)
+	}
+}
+
+func TestIsCheckmate(t *testing.T) {
+	testCases := []struct {
+		state     *logic.State
+		checkmate bool
+		hasMoves  bool
+	}{
+		{
+			state:     logic.NewInitialState(),
+			checkmate: false,
+			hasMoves:  true,
+		},
+		// mate by protected gold
+		{
+			state: logic.NewState(
+				[9][9]shogi.Piece{
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WOU},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WKY, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WKI, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+				},
+				[2]shogi.Captured{{FU: 1}, {}},
+				shogi.TurnBlack,
+			),
+			checkmate: true,
+			hasMoves:  false,
+		},
+		// no legal moves without check
+		{
+			state: logic.NewState(
+				[9][9]shogi.Piece{
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WOU},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.WKI, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.WKI, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.BOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+				},
+				[2]shogi.Captured{},
+				shogi.TurnBlack,
+			),
+			checkmate: false,
+			hasMoves:  false,
+		},
+	}
+	for i, tc := range testCases {
+		if result := tc.state.IsCheckmate(); result != tc.checkmate {
+			t.Errorf("#%d: checkmate got: %v, expected: %v", i, result, tc.checkmate)
+		}
+		if result := tc.state.HasLegalMoves(); result != tc.hasMoves {
+			t.Errorf("#%d: has legal moves got: %v, expected: %v", i, result, tc.hasMoves)
+		}
+	}
+}
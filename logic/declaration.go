@@ -0,0 +1,102 @@
+package logic
+
+import (
+	"github.com/sugyan/shogi"
+)
+
+// DeclarationRule type
+type DeclarationRule int
+
+// DeclarationRule constants
+const (
+	DeclarationRule24 DeclarationRule = iota // 24点法
+	DeclarationRule27                        // 27点法
+)
+
+// DeclarationResult type
+type DeclarationResult int
+
+// DeclarationResult constants
+const (
+	DeclarationLose DeclarationResult = iota
+	DeclarationDraw
+	DeclarationWin
+)
+
+// String method
+func (r DeclarationResult) String() string {
+	switch r {
+	case DeclarationDraw:
+		return "draw"
+	case DeclarationWin:
+		return "win"
+	}
+	return "lose"
+}
+
+// JudgeDeclaration function judges the entering king declaration by the side to move
+func JudgeDeclaration(s *State, rule DeclarationRule) DeclarationResult {
+	turn := s.turn
+	// king in the enemy camp
+	i, _, ok := s.kingSquare(turn)
+	if !ok || !inEnemyCamp(i, turn) {
+		return DeclarationLose
+	}
+	// not in check
+	if s.isChecked(turn) {
+		return DeclarationLose
+	}
+	// at least 10 pieces in the enemy camp
+	points, pieces := s.declarationPoints(turn)
+	if pieces < 10 {
+		return DeclarationLose
+	}
+	switch rule {
+	case DeclarationRule24:
+		switch {
+		case points >= 31:
+			return DeclarationWin
+		case points >= 24:
+			return DeclarationDraw
+		}
+	case DeclarationRule27:
+		if (turn == shogi.TurnBlack && points >= 28) || (turn == shogi.TurnWhite && points >= 27) {
+			return DeclarationWin
+		}
+	}
+	return DeclarationLose
+}
+
+func inEnemyCamp(i int, turn shogi.Turn) bool {
+	if turn == shogi.TurnBlack {
+		return i < 3
+	}
+	return i > 5
+}
+
+// declarationPoints returns the points of pieces in the enemy camp and in hand,
+// and the number of pieces in the enemy camp except the king
+func (s *State) declarationPoints(turn shogi.Turn) (int, int) {
+	points, pieces := 0, 0
+	for i := 0; i < 9; i++ {
+		if !inEnemyCamp(i, turn) {
+			continue
+		}
+		for j := 0; j < 9; j++ {
+			p := s.board[i][j]
+			if p == shogi.EMP || p.Turn() != turn || p.Raw() == shogi.OU {
+				continue
+			}
+			switch p.Raw() {
+			case shogi.KA, shogi.HI:
+				points += 5
+			default:
+				points++
+			}
+			pieces++
+		}
+	}
+	c := s.captured[capturedIndex(turn)]
+	points += (c.KA+c.HI)*5 + c.FU + c.KY + c.KE + c.GI + c.KI
+	return points, pieces
+}
@@ -0,0 +1,118 @@
+package logic_test
+
+import (
+	"testing"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/logic"
+)
+
+func TestJudgeDeclaration(t *testing.T) {
+	black := [9][9]shogi.Piece{
+		{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+		{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BRY},
+		{shogi.BFU, shogi.BFU, shogi.BFU, shogi.BFU, shogi.BTO, shogi.BFU, shogi.BFU, shogi.BFU, shogi.BFU},
+		{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+		{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+		{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+		{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+		{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+		{shogi.WOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+	}
+	white := [9][9]shogi.Piece{
+		{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BOU},
+		{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+		{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+		{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+		{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+		{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+		{shogi.WFU, shogi.WFU, shogi.WFU, shogi.WFU, shogi.WFU, shogi.WFU, shogi.WFU, shogi.WFU, shogi.WFU},
+		{shogi.WHI, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+		{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+	}
+	// king out of the enemy camp
+	outOfCamp := black
+	outOfCamp[1][4], outOfCamp[3][4] = shogi.EMP, shogi.BOU
+	// only 9 pieces in the enemy camp
+	fewPieces := black
+	fewPieces[2][0] = shogi.EMP
+	// in check
+	inCheck := black
+	inCheck[0][4] = shogi.WKI
+
+	testCases := []struct {
+		state    *logic.State
+		expected map[logic.DeclarationRule]logic.DeclarationResult
+	}{
+		{
+			state: logic.NewState(black, [2]shogi.Captured{{KA: 1}, {}}, shogi.TurnBlack),
+			expected: map[logic.DeclarationRule]logic.DeclarationResult{
+				logic.DeclarationRule24: logic.DeclarationLose,
+				logic.DeclarationRule27: logic.DeclarationLose,
+			},
+		},
+		{
+			state: logic.NewState(black, [2]shogi.Captured{{KA: 2, HI: 1}, {}}, shogi.TurnBlack),
+			expected: map[logic.DeclarationRule]logic.DeclarationResult{
+				logic.DeclarationRule24: logic.DeclarationDraw,
+				logic.DeclarationRule27: logic.DeclarationWin,
+			},
+		},
+		{
+			state: logic.NewState(black, [2]shogi.Captured{{KA: 2, HI: 1, KI: 2}, {}}, shogi.TurnBlack),
+			expected: map[logic.DeclarationRule]logic.DeclarationResult{
+				logic.DeclarationRule24: logic.DeclarationWin,
+				logic.DeclarationRule27: logic.DeclarationWin,
+			},
+		},
+		{
+			state: logic.NewState(black, [2]shogi.Captured{{KA: 2, HI: 1, KI: 2}, {}}, shogi.TurnWhite),
+			expected: map[logic.DeclarationRule]logic.DeclarationResult{
+				logic.DeclarationRule24: logic.DeclarationLose,
+				logic.DeclarationRule27: logic.DeclarationLose,
+			},
+		},
+		{
+			state: logic.NewState(outOfCamp, [2]shogi.Captured{{KA: 2, HI: 1, KI: 2}, {}}, shogi.TurnBlack),
+			expected: map[logic.DeclarationRule]logic.DeclarationResult{
+				logic.DeclarationRule24: logic.DeclarationLose,
+				logic.DeclarationRule27: logic.DeclarationLose,
+			},
+		},
+		{
+			state: logic.NewState(fewPieces, [2]shogi.Captured{{KA: 2, HI: 2, KI: 2}, {}}, shogi.TurnBlack),
+			expected: map[logic.DeclarationRule]logic.DeclarationResult{
+				logic.DeclarationRule24: logic.DeclarationLose,
+				logic.DeclarationRule27: logic.DeclarationLose,
+			},
+		},
+		{
+			state: logic.NewState(inCheck, [2]shogi.Captured{{KA: 2, HI: 1, KI: 2}, {}}, shogi.TurnBlack),
+			expected: map[logic.DeclarationRule]logic.DeclarationResult{
+				logic.DeclarationRule24: logic.DeclarationLose,
+				logic.DeclarationRule27: logic.DeclarationLose,
+			},
+		},
+		{
+			state: logic.NewState(white, [2]shogi.Captured{{}, {KA: 2, KI: 3}}, shogi.TurnWhite),
+			expected: map[logic.DeclarationRule]logic.DeclarationResult{
+				logic.DeclarationRule24: logic.DeclarationDraw,
+				logic.DeclarationRule27: logic.DeclarationWin,
+			},
+		},
+		{
+			state: logic.NewState(white, [2]shogi.Captured{{}, {KA: 2, KI: 2}}, shogi.TurnWhite),
+			expected: map[logic.DeclarationRule]logic.DeclarationResult{
+				logic.DeclarationRule24: logic.DeclarationDraw,
+				logic.DeclarationRule27: logic.DeclarationLose,
+			},
+		},
+	}
+	for i, tc := range testCases {
+		for rule, expected := range tc.expected {
+			if result := logic.JudgeDeclaration(tc.state, rule); result != expected {
+				t.Errorf("#%d: rule %d got %v, expected: %v", i, rule, result, expected)
+			}
+		}
+	}
+}
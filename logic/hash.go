@@ -51,3 +51,40 @@ func init() {
 		hasher.turn[turn] = r.Uint64()
 	}
 }
+
+func (z *zobrist) hash(board [9][9]shogi.Piece, captured [2]shogi.Captured, turn shogi.Turn) uint64 {
+	hash := z.turn[turn]
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			piece := board[i][j]
+			if piece != shogi.EMP {
+				hash += z.board[piece][i][j]
+			}
+		}
+	}
+	for _, turn := range []shogi.Turn{shogi.TurnBlack, shogi.TurnWhite} {
+		c := captured[capturedIndex(turn)]
+		for i := 0; i < c.FU; i++ {
+			hash += z.captured[shogi.FU][turn]
+		}
+		for i := 0; i < c.KY; i++ {
+			hash += z.captured[shogi.KY][turn]
+		}
+		for i := 0; i < c.KE; i++ {
+			hash += z.captured[shogi.KE][turn]
+		}
+		for i := 0; i < c.GI; i++ {
+			hash += z.captured[shogi.GI][turn]
+		}
+		for i := 0; i < c.KI; i++ {
+			hash += z.captured[shogi.KI][turn]
+		}
+		for i := 0; i < c.KA; i++ {
+			hash += z.captured[shogi.KA][turn]
+		}
+		for i := 0; i < c.HI; i++ {
+			hash += z.captured[shogi.HI][turn]
+		}
+	}
+	return hash
+}
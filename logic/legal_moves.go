@@ -47,6 +47,18 @@ func init() {
 
 // LegalMoves method
 func (s *State) LegalMoves() []*shogi.Move {
+	moves := []*shogi.Move{}
+	for _, move := range s.candidateMoves() {
+		if !s.isSelfCheck(move) && !s.IsUchifuzume(move) {
+			moves = append(moves, move)
+		}
+	}
+	return moves
+}
+
+// candidateMoves returns all moves which follow the piece movement rules,
+// including ones which leave the own king in check
+func (s *State) candidateMoves() []*shogi.Move {
 	moves := []*shogi.Move{}
 	for i := 0; i < 9; i++ {
 		for j := 0; j < 9; j++ {
@@ -140,6 +152,9 @@ func (s *State) LegalMoves() []*shogi.Move {
 		}
 		if captured.FU > 0 {
 			for _, position := range positions {
+				if s.IsNifu(s.turn, position.File) {
+					continue
+				}
 				if (s.turn == shogi.TurnBlack && position.Rank > 1) || (s.turn == shogi.TurnWhite && position.Rank < 9) {
 					capturedMoves = append(capturedMoves, &shogi.Move{
 						Src:   shogi.Position{File: 0, Rank: 0},
@@ -212,3 +227,33 @@ func (s *State) LegalMoves() []*shogi.Move {
 
 	return moves
 }
+
+// IsUchifuzume method reports whether the move is a pawn drop which gives checkmate
+func (s *State) IsUchifuzume(move *shogi.Move) bool {
+	if move.Src != (shogi.Position{File: 0, Rank: 0}) || move.Piece.Raw() != shogi.FU {
+		return false
+	}
+	if piece, err := s.GetPiece(move.Dst.File, move.Dst.Rank); err != nil || piece != shogi.EMP {
+		return false
+	}
+	state := *s
+	state.apply(move)
+	state.turn = !move.Piece.Turn()
+	if !state.isChecked(state.turn) {
+		return false
+	}
+	// check by a pawn cannot be interposed, so only moves on the board can escape from it
+	for _, m := range state.candidateMoves() {
+		if m.Src != (shogi.Position{File: 0, Rank: 0}) && !state.isSelfCheck(m) {
+			return false
+		}
+	}
+	return true
+}
+
+// isSelfCheck reports whether the move leaves the king of the mover attacked
+func (s *State) isSelfCheck(move *shogi.Move) bool {
+	state := *s
+	state.apply(move)
+	return state.isChecked(move.Piece.Turn())
+}
@@ -1,6 +1,7 @@
 package logic_test
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -23,10 +24,13 @@ func TestLegalMovesInRecord(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	matches, err := filepath.Glob(filepath.Join(dir, "testdata", "*.csa"))
+	matches, err := filepath.Glob(filepath.Join(dir, "..", "testdata", "*.csa"))
 	if err != nil {
 		t.Fatal(err)
 	}
+	if len(matches) == 0 {
+		t.Fatal("no records found")
+	}
 	for i, match := range matches {
 		file, err := os.Open(match)
 		if err != nil {
@@ -143,3 +147,233 @@ func TestLegalMovesImpossibleMoves(t *testing.T) {
 		}
 	}
 }
+
+func TestLegalMovesSelfCheck(t *testing.T) {
+	contains := func(moves []*shogi.Move, move *shogi.Move) bool {
+		for _, m := range moves {
+			if *m == *move {
+				return true
+			}
+		}
+		return false
+	}
+	move := func(srcFile, srcRank, dstFile, dstRank int, piece shogi.Piece) *shogi.Move {
+		return &shogi.Move{
+			Src:   shogi.Position{File: srcFile, Rank: srcRank},
+			Dst:   shogi.Position{File: dstFile, Rank: dstRank},
+			Piece: piece,
+		}
+	}
+	testCases := []struct {
+		state    *logic.State
+		legal    []*shogi.Move
+		illegal  []*shogi.Move
+		numMoves int
+	}{
+		// pinned gold
+		{
+			state: logic.NewState(
+				[9][9]shogi.Piece{
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WHI, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WOU},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BKI, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+				},
+				[2]shogi.Captured{},
+				shogi.TurnBlack,
+			),
+			legal: []*shogi.Move{
+				move(5, 8, 5, 7, shogi.BKI),
+				move(5, 9, 4, 9, shogi.BOU),
+				move(5, 9, 6, 8, shogi.BOU),
+			},
+			illegal: []*shogi.Move{
+				move(5, 8, 4, 7, shogi.BKI),
+				move(5, 8, 6, 7, shogi.BKI),
+				move(5, 8, 4, 8, shogi.BKI),
+				move(5, 8, 6, 8, shogi.BKI),
+			},
+			numMoves: 5,
+		},
+		// check by rook
+		{
+			state: logic.NewState(
+				[9][9]shogi.Piece{
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WHI, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WOU},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BFU},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+				},
+				[2]shogi.Captured{
+					{KI: 1},
+					{},
+				},
+				shogi.TurnBlack,
+			),
+			legal: []*shogi.Move{
+				move(0, 0, 5, 5, shogi.BKI),
+				move(0, 0, 5, 2, shogi.BKI),
+				move(5, 9, 4, 8, shogi.BOU),
+				move(5, 9, 6, 9, shogi.BOU),
+			},
+			illegal: []*shogi.Move{
+				move(1, 7, 1, 6, shogi.BFU),
+				move(5, 9, 5, 8, shogi.BOU),
+				move(0, 0, 4, 4, shogi.BKI),
+			},
+			numMoves: 11,
+		},
+		// king cannot capture a protected piece
+		{
+			state: logic.NewState(
+				[9][9]shogi.Piece{
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WOU},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WKY, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WKI, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+				},
+				[2]shogi.Captured{},
+				shogi.TurnBlack,
+			),
+			legal:    []*shogi.Move{},
+			illegal:  []*shogi.Move{move(5, 9, 5, 8, shogi.BOU)},
+			numMoves: 0,
+		},
+	}
+	for i, tc := range testCases {
+		moves := tc.state.LegalMoves()
+		for _, m := range tc.legal {
+			if !contains(moves, m) {
+				t.Errorf("#%d: move %v should be legal", i, m)
+			}
+		}
+		for _, m := range tc.illegal {
+			if contains(moves, m) {
+				t.Errorf("#%d: move %v should be illegal", i, m)
+			}
+		}
+		if len(moves) != tc.numMoves {
+			t.Errorf("#%d: number of legal moves got: %d, expected: %d", i, len(moves), tc.numMoves)
+		}
+	}
+}
+
+func TestLegalMovesNifu(t *testing.T) {
+	s := logic.NewInitialState()
+	s.SetPiece(5, 7, shogi.EMP)
+	s.UpdateCaptured(shogi.TurnBlack, 1, 0, 0, 0, 0, 0, 0)
+	drops := 0
+	for _, move := range s.LegalMoves() {
+		if move.Src == (shogi.Position{File: 0, Rank: 0}) {
+			if move.Dst.File != 5 {
+				t.Errorf("move %v should be nifu", move)
+			}
+			drops++
+		}
+	}
+	if drops != 6 {
+		t.Errorf("number of drops got: %d, expected: %d", drops, 6)
+	}
+}
+
+func TestUchifuzume(t *testing.T) {
+	drop := &shogi.Move{
+		Src:   shogi.Position{File: 0, Rank: 0},
+		Dst:   shogi.Position{File: 1, Rank: 2},
+		Piece: shogi.BFU,
+	}
+	testCases := []struct {
+		state    *logic.State
+		expected bool
+	}{
+		// protected by gold
+		{
+			state: logic.NewState(
+				[9][9]shogi.Piece{
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WKE, shogi.WOU},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WKY, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BKI},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.BOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+				},
+				[2]shogi.Captured{{FU: 1}, {}},
+				shogi.TurnBlack,
+			),
+			expected: true,
+		},
+		// king can capture the pawn
+		{
+			state: logic.NewState(
+				[9][9]shogi.Piece{
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WKE, shogi.WOU},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WKY, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BKI},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.BOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+				},
+				[2]shogi.Captured{{FU: 1}, {}},
+				shogi.TurnBlack,
+			),
+			expected: false,
+		},
+		// king can escape
+		{
+			state: logic.NewState(
+				[9][9]shogi.Piece{
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WOU},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WKY, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BKI},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+					{shogi.BOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+				},
+				[2]shogi.Captured{{FU: 1}, {}},
+				shogi.TurnBlack,
+			),
+			expected: false,
+		},
+	}
+	for i, tc := range testCases {
+		if result := tc.state.IsUchifuzume(drop); result != tc.expected {
+			t.Errorf("#%d: got %v, expected: %v", i, result, tc.expected)
+		}
+		legal := false
+		for _, move := range tc.state.LegalMoves() {
+			if *move == *drop {
+				legal = true
+			}
+		}
+		if legal == tc.expected {
+			t.Errorf("#%d: legal got %v, expected: %v", i, legal, !tc.expected)
+		}
+		if err := tc.state.Clone().Move(drop); tc.expected && (err != shogi.ErrUchifuzume || !errors.Is(err, shogi.ErrInvalidMove)) {
+			t.Errorf("#%d: move error got %v, expected: %v", i, err, shogi.ErrUchifuzume)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package logic
+
+import (
+	"github.com/sugyan/shogi"
+)
+
+// Sennichite type
+type Sennichite int
+
+// Sennichite constants
+const (
+	SennichiteNone                Sennichite = iota
+	SennichiteDraw                           // 千日手
+	SennichitePerpetualCheckBlack            // 連続王手の千日手 (先手の負け)
+	SennichitePerpetualCheckWhite            // 連続王手の千日手 (後手の負け)
+)
+
+// String method
+func (s Sennichite) String() string {
+	switch s {
+	case SennichiteDraw:
+		return "sennichite"
+	case SennichitePerpetualCheckBlack:
+		return "perpetual check by black"
+	case SennichitePerpetualCheckWhite:
+		return "perpetual check by white"
+	}
+	return "none"
+}
+
+// Loser method returns the turn of the side which loses by the sennichite
+func (s Sennichite) Loser() (shogi.Turn, bool) {
+	switch s {
+	case SennichitePerpetualCheckBlack:
+		return shogi.TurnBlack, true
+	case SennichitePerpetualCheckWhite:
+		return shogi.TurnWhite, true
+	}
+	return shogi.TurnBlack, false
+}
+
+type position struct {
+	hash  uint64
+	turn  shogi.Turn
+	check bool
+}
+
+// Repetition struct keeps the history of positions to detect sennichite
+type Repetition struct {
+	positions []position
+	counts    map[uint64]int
+}
+
+// NewRepetition function
+func NewRepetition(state *State) *Repetition {
+	r := &Repetition{
+		positions: []position{},
+		counts:    map[uint64]int{},
+	}
+	r.Push(state)
+	return r
+}
+
+// Push method adds the position of the state and reports the sennichite if it occurs
+func (r *Repetition) Push(state *State) Sennichite {
+	p := position{
+		hash:  state.Hash,
+		turn:  state.turn,
+		check: state.InCheck(),
+	}
+	r.positions = append(r.positions, p)
+	r.counts[p.hash]++
+	if r.counts[p.hash] < 4 {
+		return SennichiteNone
+	}
+	first := 0
+	for i, q := range r.positions {
+		if q.hash == p.hash {
+			first = i
+			break
+		}
+	}
+	// whether all moves of each side in the cycle gave check
+	checks := map[shogi.Turn]bool{
+		shogi.TurnBlack: true,
+		shogi.TurnWhite: true,
+	}
+	for _, q := range r.positions[first+1:] {
+		if !q.check {
+			checks[!q.turn] = false
+		}
+	}
+	switch {
+	case checks[shogi.TurnBlack] && !checks[shogi.TurnWhite]:
+		return SennichitePerpetualCheckBlack
+	case checks[shogi.TurnWhite] && !checks[shogi.TurnBlack]:
+		return SennichitePerpetualCheckWhite
+	}
+	return SennichiteDraw
+}
+
+// DetectSennichite function replays the moves from the state and returns the first sennichite
+func DetectSennichite(state *State, moves ...*shogi.Move) (Sennichite, error) {
+	s := *state
+	r := NewRepetition(&s)
+	for _, move := range moves {
+		if err := s.Move(move); err != nil {
+			return SennichiteNone, err
+		}
+		if result := r.Push(&s); result != SennichiteNone {
+			return result, nil
+		}
+	}
+	return SennichiteNone, nil
+}
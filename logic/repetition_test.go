@@ -0,0 +1,97 @@
+package logic_test
+
+import (
+	"testing"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/logic"
+)
+
+func TestDetectSennichite(t *testing.T) {
+	move := func(srcFile, srcRank, dstFile, dstRank int, piece shogi.Piece) *shogi.Move {
+		return &shogi.Move{
+			Src:   shogi.Position{File: srcFile, Rank: srcRank},
+			Dst:   shogi.Position{File: dstFile, Rank: dstRank},
+			Piece: piece,
+		}
+	}
+	repeat := func(n int, moves ...*shogi.Move) []*shogi.Move {
+		results := []*shogi.Move{}
+		for i := 0; i < n; i++ {
+			results = append(results, moves...)
+		}
+		return results
+	}
+	checkState := logic.NewState(
+		[9][9]shogi.Piece{
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WOU},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BHI, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.BOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+		},
+		[2]shogi.Captured{},
+		shogi.TurnBlack,
+	)
+	testCases := []struct {
+		state    *logic.State
+		moves    []*shogi.Move
+		expected logic.Sennichite
+	}{
+		// 3 times
+		{
+			state: logic.NewInitialState(),
+			moves: repeat(2,
+				move(5, 9, 5, 8, shogi.BOU),
+				move(5, 1, 5, 2, shogi.WOU),
+				move(5, 8, 5, 9, shogi.BOU),
+				move(5, 2, 5, 1, shogi.WOU),
+			),
+			expected: logic.SennichiteNone,
+		},
+		// 4 times
+		{
+			state: logic.NewInitialState(),
+			moves: repeat(3,
+				move(5, 9, 5, 8, shogi.BOU),
+				move(5, 1, 5, 2, shogi.WOU),
+				move(5, 8, 5, 9, shogi.BOU),
+				move(5, 2, 5, 1, shogi.WOU),
+			),
+			expected: logic.SennichiteDraw,
+		},
+		// perpetual check
+		{
+			state: checkState,
+			moves: append(
+				[]*shogi.Move{move(3, 5, 3, 1, shogi.BHI)},
+				repeat(3,
+					move(1, 1, 1, 2, shogi.WOU),
+					move(3, 1, 3, 2, shogi.BHI),
+					move(1, 2, 1, 1, shogi.WOU),
+					move(3, 2, 3, 1, shogi.BHI),
+				)...,
+			),
+			expected: logic.SennichitePerpetualCheckBlack,
+		},
+	}
+	for i, tc := range testCases {
+		result, err := logic.DetectSennichite(tc.state, tc.moves...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != tc.expected {
+			t.Errorf("#%d: got %v, expected: %v", i, result, tc.expected)
+		}
+	}
+	if loser, ok := logic.SennichitePerpetualCheckBlack.Loser(); !ok || loser != shogi.TurnBlack {
+		t.Errorf("loser got %v, %v, expected: %v, %v", loser, ok, shogi.TurnBlack, true)
+	}
+	if _, ok := logic.SennichiteDraw.Loser(); ok {
+		t.Errorf("draw should not have loser")
+	}
+}
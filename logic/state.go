@@ -22,6 +22,45 @@ func capturedIndex(turn shogi.Turn) int {
 	return 0
 }
 
+func numCaptured(c shogi.Captured, raw shogi.RawPiece) int {
+	switch raw {
+	case shogi.FU:
+		return c.FU
+	case shogi.KY:
+		return c.KY
+	case shogi.KE:
+		return c.KE
+	case shogi.GI:
+		return c.GI
+	case shogi.KI:
+		return c.KI
+	case shogi.KA:
+		return c.KA
+	case shogi.HI:
+		return c.HI
+	}
+	return 0
+}
+
+func addCaptured(c *shogi.Captured, raw shogi.RawPiece, n int) {
+	switch raw {
+	case shogi.FU:
+		c.FU += n
+	case shogi.KY:
+		c.KY += n
+	case shogi.KE:
+		c.KE += n
+	case shogi.GI:
+		c.GI += n
+	case shogi.KI:
+		c.KI += n
+	case shogi.KA:
+		c.KA += n
+	case shogi.HI:
+		c.HI += n
+	}
+}
+
 // NewInitialState function
 func NewInitialState() *State {
 	return NewState(
@@ -43,44 +82,11 @@ func NewInitialState() *State {
 
 // NewState function
 func NewState(board [9][9]shogi.Piece, captured [2]shogi.Captured, turn shogi.Turn) *State {
-	hash := hasher.turn[turn]
-	for i := 0; i < 9; i++ {
-		for j := 0; j < 9; j++ {
-			piece := board[i][j]
-			if piece != shogi.EMP {
-				hash += hasher.board[piece][i][j]
-			}
-		}
-	}
-	for _, turn := range []shogi.Turn{shogi.TurnBlack, shogi.TurnWhite} {
-		c := captured[capturedIndex(turn)]
-		for i := 0; i < c.FU; i++ {
-			hash += hasher.captured[shogi.FU][turn]
-		}
-		for i := 0; i < c.KY; i++ {
-			hash += hasher.captured[shogi.KY][turn]
-		}
-		for i := 0; i < c.KE; i++ {
-			hash += hasher.captured[shogi.KE][turn]
-		}
-		for i := 0; i < c.GI; i++ {
-			hash += hasher.captured[shogi.GI][turn]
-		}
-		for i := 0; i < c.KI; i++ {
-			hash += hasher.captured[shogi.KI][turn]
-		}
-		for i := 0; i < c.KA; i++ {
-			hash += hasher.captured[shogi.KA][turn]
-		}
-		for i := 0; i < c.HI; i++ {
-			hash += hasher.captured[shogi.HI][turn]
-		}
-	}
 	return &State{
 		board:    board,
 		captured: captured,
 		turn:     turn,
-		Hash:     hash,
+		Hash:     hasher.hash(board, captured, turn),
 	}
 }
 
@@ -122,6 +128,12 @@ func (s *State) UpdateCaptured(turn shogi.Turn, fu, ky, ke, gi, ki, ka, hi int)
 	s.captured[idx].KI += ki
 	s.captured[idx].KA += ka
 	s.captured[idx].HI += hi
+	// update hash
+	for raw, n := range map[shogi.RawPiece]int{
+		shogi.FU: fu, shogi.KY: ky, shogi.KE: ke, shogi.GI: gi, shogi.KI: ki, shogi.KA: ka, shogi.HI: hi,
+	} {
+		s.Hash += uint64(n) * hasher.captured[raw][turn]
+	}
 }
 
 // Turn method for shogi.State interface
@@ -131,8 +143,9 @@ func (s *State) Turn() shogi.Turn {
 
 // SetTurn method for shogi.State interface
 func (s *State) SetTurn(turn shogi.Turn) {
+	// update hash
+	s.Hash += hasher.turn[turn] - hasher.turn[s.turn]
 	s.turn = turn
-	// TODO: update hash
 }
 
 // Equals method for shogi.State interface
@@ -173,66 +186,57 @@ func (s *State) Clone() shogi.State {
 
 // Move method for shogi.State interface
 func (s *State) Move(moves ...*shogi.Move) error {
+	state := *s
 	for _, move := range moves {
-		capturedIndex := 0
-		if move.Piece.Turn() == shogi.TurnWhite {
-			capturedIndex = 1
+		if err := state.validate(move); err != nil {
+			return err
 		}
-		if move.Src.File == 0 && move.Src.Rank == 0 {
-			// use captured piece
-			switch move.Piece.Raw() {
-			case shogi.FU:
-				s.captured[capturedIndex].FU--
-			case shogi.KY:
-				s.captured[capturedIndex].KY--
-			case shogi.KE:
-				s.captured[capturedIndex].KE--
-			case shogi.GI:
-				s.captured[capturedIndex].GI--
-			case shogi.KI:
-				s.captured[capturedIndex].KI--
-			case shogi.KA:
-				s.captured[capturedIndex].KA--
-			case shogi.HI:
-				s.captured[capturedIndex].HI--
-			}
-		} else {
-			// move piece
-			src := s.board[move.Src.Rank-1][9-move.Src.File]
-			dst := s.board[move.Dst.Rank-1][9-move.Dst.File]
-			// TODO: check invalid move
-			if src != move.Piece && src.Promote() != move.Piece {
-				return shogi.ErrInvalidMove
-			}
-			if dst != shogi.EMP {
-				if dst.Turn() == src.Turn() {
-					return shogi.ErrInvalidMove
-				}
-				switch dst.Raw() {
-				case shogi.FU:
-					s.captured[capturedIndex].FU++
-				case shogi.KY:
-					s.captured[capturedIndex].KY++
-				case shogi.KE:
-					s.captured[capturedIndex].KE++
-				case shogi.GI:
-					s.captured[capturedIndex].GI++
-				case shogi.KI:
-					s.captured[capturedIndex].KI++
-				case shogi.KA:
-					s.captured[capturedIndex].KA++
-				case shogi.HI:
-					s.captured[capturedIndex].HI++
-				}
-			}
-			s.board[move.Src.Rank-1][9-move.Src.File] = shogi.EMP
-		}
-		s.board[move.Dst.Rank-1][9-move.Dst.File] = move.Piece
-		s.turn = !s.turn
+		state.apply(move)
 	}
+	*s = state
 	return nil
 }
 
+// apply updates the state by the move without any validation
+func (s *State) apply(move *shogi.Move) {
+	c := &s.captured[capturedIndex(move.Piece.Turn())]
+	if move.Src == (shogi.Position{File: 0, Rank: 0}) {
+		// use captured piece
+		addCaptured(c, move.Piece.Raw(), -1)
+		s.Hash -= hasher.captured[move.Piece.Raw()][move.Piece.Turn()]
+	} else {
+		// move piece
+		src := s.board[move.Src.Rank-1][9-move.Src.File]
+		dst := s.board[move.Dst.Rank-1][9-move.Dst.File]
+		if dst != shogi.EMP {
+			addCaptured(c, dst.Raw(), 1)
+			s.Hash += hasher.captured[dst.Raw()][move.Piece.Turn()]
+			s.Hash -= hasher.board[dst][move.Dst.Rank-1][9-move.Dst.File]
+		}
+		s.board[move.Src.Rank-1][9-move.Src.File] = shogi.EMP
+		s.Hash -= hasher.board[src][move.Src.Rank-1][9-move.Src.File]
+	}
+	s.board[move.Dst.Rank-1][9-move.Dst.File] = move.Piece
+	s.Hash += hasher.board[move.Piece][move.Dst.Rank-1][9-move.Dst.File]
+	s.Hash += hasher.turn[!s.turn] - hasher.turn[s.turn]
+	s.turn = !s.turn
+}
+
+// IsNifu method reports whether the file already has an unpromoted pawn of turn,
+// so that a pawn of turn cannot be dropped on it
+func (s *State) IsNifu(turn shogi.Turn, file int) bool {
+	if file < 1 || file > 9 {
+		return false
+	}
+	pawn := shogi.MakePiece(shogi.FU, turn)
+	for i := 0; i < 9; i++ {
+		if s.board[i][9-file] == pawn {
+			return true
+		}
+	}
+	return false
+}
+
 // String method for shogi.State interface
 func (s *State) String() string {
 	b := &strings.Builder{}
@@ -1,9 +1,13 @@
 package logic_test
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/format/csa"
 	"github.com/sugyan/shogi/logic"
 )
 
@@ -46,3 +50,177 @@ P9+KY+KE+GI+KI+OU+KI+GI+KE+KY`[1:],
 		}
 	}
 }
+
+func TestIsNifu(t *testing.T) {
+	s := logic.NewState(
+		[9][9]shogi.Piece{
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.WFU, shogi.EMP, shogi.WTO, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.BFU, shogi.EMP, shogi.BTO, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+		},
+		[2]shogi.Captured{{FU: 1}, {FU: 1}},
+		shogi.TurnBlack,
+	)
+	testCases := []struct {
+		turn     shogi.Turn
+		file     int
+		expected bool
+	}{
+		{shogi.TurnBlack, 9, false},
+		{shogi.TurnBlack, 8, true},
+		{shogi.TurnBlack, 7, false},
+		{shogi.TurnBlack, 6, false},
+		{shogi.TurnWhite, 9, true},
+		{shogi.TurnWhite, 8, false},
+		{shogi.TurnWhite, 7, false},
+		{shogi.TurnWhite, 0, false},
+	}
+	for i, tc := range testCases {
+		if result := s.IsNifu(tc.turn, tc.file); result != tc.expected {
+			t.Errorf("#%d: got %v, expected: %v", i, result, tc.expected)
+		}
+	}
+	// drop on the file with a pawn
+	if err := s.Clone().Move(&shogi.Move{
+		Src:   shogi.Position{File: 0, Rank: 0},
+		Dst:   shogi.Position{File: 8, Rank: 5},
+		Piece: shogi.BFU,
+	}); err != shogi.ErrNifu {
+		t.Errorf("got %v, expected: %v", err, shogi.ErrNifu)
+	}
+	// drop on the file with a promoted pawn
+	if err := s.Clone().Move(&shogi.Move{
+		Src:   shogi.Position{File: 0, Rank: 0},
+		Dst:   shogi.Position{File: 6, Rank: 5},
+		Piece: shogi.BFU,
+	}); err != nil {
+		t.Errorf("got %v, expected: %v", err, nil)
+	}
+}
+
+func TestHash(t *testing.T) {
+	rebuild := func(s shogi.State) *logic.State {
+		board := [9][9]shogi.Piece{}
+		for i := 0; i < 9; i++ {
+			for j := 0; j < 9; j++ {
+				board[i][j], _ = s.GetPiece(9-j, i+1)
+			}
+		}
+		return logic.NewState(
+			board,
+			[2]shogi.Captured{s.GetCaptured(shogi.TurnBlack), s.GetCaptured(shogi.TurnWhite)},
+			s.Turn(),
+		)
+	}
+	// records
+	matches, err := filepath.Glob(filepath.Join("..", "testdata", "*.csa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, match := range matches {
+		file, err := os.Open(match)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		record, err := csa.Parse(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := record.State.(*logic.State)
+		for j, move := range record.Moves {
+			if err := s.Move(move); err != nil {
+				t.Fatal(err)
+			}
+			if expected := rebuild(s).Hash; s.Hash != expected {
+				t.Errorf("#%d-%d: hash got: %v, expected: %v", i, j, s.Hash, expected)
+			}
+		}
+	}
+	// setters
+	s := logic.NewInitialState()
+	s.SetTurn(shogi.TurnWhite)
+	s.SetPiece(2, 2, shogi.EMP)
+	s.SetPiece(5, 5, shogi.BKA)
+	s.UpdateCaptured(shogi.TurnBlack, 2, 0, 0, 0, 0, 1, 0)
+	s.UpdateCaptured(shogi.TurnWhite, 1, 0, 0, 0, 1, 0, 0)
+	s.UpdateCaptured(shogi.TurnBlack, -1, 0, 0, 0, 0, 0, 0)
+	if expected := rebuild(s).Hash; s.Hash != expected {
+		t.Errorf("hash got: %v, expected: %v", s.Hash, expected)
+	}
+	if s.Hash == logic.NewInitialState().Hash {
+		t.Errorf("hash should be changed")
+	}
+}
+
+func TestMoveErrors(t *testing.T) {
+	move := func(srcFile, srcRank, dstFile, dstRank int, piece shogi.Piece) *shogi.Move {
+		return &shogi.Move{
+			Src:   shogi.Position{File: srcFile, Rank: srcRank},
+			Dst:   shogi.Position{File: dstFile, Rank: dstRank},
+			Piece: piece,
+		}
+	}
+	state := logic.NewState(
+		[9][9]shogi.Piece{
+			{shogi.WOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.WHI, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BFU},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BGI, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BKI, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+			{shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP, shogi.BOU, shogi.EMP, shogi.EMP, shogi.EMP, shogi.EMP},
+		},
+		[2]shogi.Captured{{FU: 1}, {}},
+		shogi.TurnBlack,
+	)
+	testCases := []struct {
+		moves    []*shogi.Move
+		expected error
+	}{
+		{[]*shogi.Move{move(1, 2, 1, 1, shogi.BTO)}, nil},
+		{[]*shogi.Move{move(3, 7, 2, 6, shogi.BGI)}, nil},
+		{[]*shogi.Move{move(5, 1, 5, 2, shogi.WHI)}, shogi.ErrWrongTurn},
+		{[]*shogi.Move{move(1, 2, 1, 0, shogi.BTO)}, shogi.ErrOutOfBoard},
+		{[]*shogi.Move{move(1, 10, 1, 1, shogi.BTO)}, shogi.ErrOutOfBoard},
+		{[]*shogi.Move{move(5, 5, 5, 4, shogi.BFU)}, shogi.ErrPieceNotFound},
+		{[]*shogi.Move{move(3, 7, 3, 6, shogi.BKI)}, shogi.ErrPieceNotFound},
+		{[]*shogi.Move{move(3, 7, 3, 8, shogi.BGI)}, shogi.ErrUnreachable},
+		{[]*shogi.Move{move(5, 9, 5, 8, shogi.BOU)}, shogi.ErrUnreachable},
+		{[]*shogi.Move{move(0, 0, 5, 5, shogi.BGI)}, shogi.ErrNotInHand},
+		{[]*shogi.Move{move(0, 0, 2, 1, shogi.BFU)}, shogi.ErrInvalidDrop},
+		{[]*shogi.Move{move(0, 0, 4, 1, shogi.BTO)}, shogi.ErrInvalidDrop},
+		{[]*shogi.Move{move(0, 0, 5, 1, shogi.BFU)}, shogi.ErrInvalidDrop},
+		{[]*shogi.Move{move(0, 0, 1, 5, shogi.BFU)}, shogi.ErrNifu},
+		{[]*shogi.Move{move(1, 2, 1, 1, shogi.BFU)}, shogi.ErrInvalidPromotion},
+		{[]*shogi.Move{move(3, 7, 2, 6, shogi.BNG)}, shogi.ErrInvalidPromotion},
+		{[]*shogi.Move{move(5, 8, 5, 7, shogi.BKI.Promote())}, shogi.ErrInvalidPromotion},
+		{[]*shogi.Move{move(5, 8, 4, 8, shogi.BKI)}, shogi.ErrSelfCheck},
+		{[]*shogi.Move{move(5, 9, 4, 9, shogi.BOU), move(5, 1, 5, 8, shogi.WRY)}, nil},
+		{[]*shogi.Move{move(3, 7, 2, 6, shogi.BGI), move(5, 1, 5, 9, shogi.WRY)}, shogi.ErrUnreachable},
+	}
+	for i, tc := range testCases {
+		s := state.Clone()
+		err := s.Move(tc.moves...)
+		if err != tc.expected {
+			t.Errorf("#%d: err got: %v, expected: %v", i, err, tc.expected)
+		}
+		if err != nil && !errors.Is(err, shogi.ErrInvalidMove) {
+			t.Errorf("#%d: %v should be %v", i, err, shogi.ErrInvalidMove)
+		}
+		if err == shogi.ErrOutOfBoard && !errors.Is(err, shogi.ErrInvalidPosition) {
+			t.Errorf("#%d: %v should be %v", i, err, shogi.ErrInvalidPosition)
+		}
+		if err != nil && !s.Equals(state) {
+			t.Errorf("#%d: state should not be modified: %v", i, s)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package logic
+
+import (
+	"github.com/sugyan/shogi"
+)
+
+// Undo struct holds the information to take back a move
+type Undo struct {
+	move     shogi.Move
+	src      shogi.Piece
+	captured shogi.Piece
+	turn     shogi.Turn
+	hash     uint64
+}
+
+// MoveWithUndo method applies the move and returns the Undo to take it back
+func (s *State) MoveWithUndo(move *shogi.Move) (*Undo, error) {
+	u := &Undo{
+		move: *move,
+		turn: s.turn,
+		hash: s.Hash,
+	}
+	if move.Src != (shogi.Position{File: 0, Rank: 0}) {
+		src, err := s.GetPiece(move.Src.File, move.Src.Rank)
+		if err != nil {
+			return nil, err
+		}
+		u.src = src
+	}
+	captured, err := s.GetPiece(move.Dst.File, move.Dst.Rank)
+	if err != nil {
+		return nil, err
+	}
+	u.captured = captured
+	if err := s.Move(move); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// Undo method takes back the move applied by MoveWithUndo
+func (s *State) Undo(u *Undo) error {
+	piece, err := s.GetPiece(u.move.Dst.File, u.move.Dst.Rank)
+	if err != nil {
+		return err
+	}
+	if piece != u.move.Piece {
+		return shogi.ErrInvalidMove
+	}
+	c := &s.captured[capturedIndex(u.move.Piece.Turn())]
+	s.board[u.move.Dst.Rank-1][9-u.move.Dst.File] = u.captured
+	if u.move.Src == (shogi.Position{File: 0, Rank: 0}) {
+		addCaptured(c, u.move.Piece.Raw(), 1)
+	} else {
+		s.board[u.move.Src.Rank-1][9-u.move.Src.File] = u.src
+		if u.captured != shogi.EMP {
+			addCaptured(c, u.captured.Raw(), -1)
+		}
+	}
+	s.turn = u.turn
+	s.Hash = u.hash
+	return nil
+}
@@ -0,0 +1,60 @@
+package logic_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/format/csa"
+	"github.com/sugyan/shogi/logic"
+)
+
+func TestUndo(t *testing.T) {
+	matches, err := filepath.Glob(filepath.Join("..", "testdata", "*.csa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, match := range matches {
+		file, err := os.Open(match)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		record, err := csa.Parse(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := record.State.(*logic.State)
+		states := []*logic.State{}
+		undos := []*logic.Undo{}
+		for _, move := range record.Moves {
+			states = append(states, s.Clone().(*logic.State))
+			u, err := s.MoveWithUndo(move)
+			if err != nil {
+				t.Fatal(err)
+			}
+			undos = append(undos, u)
+		}
+		for j := len(undos) - 1; j >= 0; j-- {
+			if err := s.Undo(undos[j]); err != nil {
+				t.Fatal(err)
+			}
+			if !s.Equals(states[j]) {
+				t.Errorf("#%d-%d: state got: %v, expected: %v", i, j, s, states[j])
+			}
+			if s.Hash != states[j].Hash {
+				t.Errorf("#%d-%d: hash got: %v, expected: %v", i, j, s.Hash, states[j].Hash)
+			}
+		}
+		if err := s.Undo(undos[0]); err != shogi.ErrInvalidMove {
+			t.Errorf("#%d: got %v, expected: %v", i, err, shogi.ErrInvalidMove)
+		}
+		// walk forward again
+		for j, move := range record.Moves {
+			if err := s.Move(move); err != nil {
+				t.Fatalf("#%d-%d: %v", i, j, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package logic
+
+import (
+	"github.com/sugyan/shogi"
+)
+
+// validate returns the reason why the move is not allowed in the state
+func (s *State) validate(move *shogi.Move) error {
+	if move.Piece.Turn() != s.turn {
+		return shogi.ErrWrongTurn
+	}
+	if move.Dst.File < 1 || move.Dst.File > 9 || move.Dst.Rank < 1 || move.Dst.Rank > 9 {
+		return shogi.ErrOutOfBoard
+	}
+	i, j := move.Dst.Rank-1, 9-move.Dst.File
+	if move.Src == (shogi.Position{File: 0, Rank: 0}) {
+		// use captured piece
+		switch move.Piece.Raw() {
+		case shogi.FU, shogi.KY, shogi.KE, shogi.GI, shogi.KI, shogi.KA, shogi.HI:
+		default:
+			return shogi.ErrInvalidDrop
+		}
+		if move.Piece.IsPromoted() {
+			return shogi.ErrInvalidDrop
+		}
+		if numCaptured(s.captured[capturedIndex(s.turn)], move.Piece.Raw()) < 1 {
+			return shogi.ErrNotInHand
+		}
+		if s.board[i][j] != shogi.EMP || !canMoveFurther(move.Piece, i) {
+			return shogi.ErrInvalidDrop
+		}
+		if move.Piece.Raw() == shogi.FU && s.IsNifu(s.turn, move.Dst.File) {
+			return shogi.ErrNifu
+		}
+		if s.IsUchifuzume(move) {
+			return shogi.ErrUchifuzume
+		}
+	} else {
+		// move piece
+		if move.Src.File < 1 || move.Src.File > 9 || move.Src.Rank < 1 || move.Src.Rank > 9 {
+			return shogi.ErrOutOfBoard
+		}
+		ii, jj := move.Src.Rank-1, 9-move.Src.File
+		src := s.board[ii][jj]
+		if src == shogi.EMP || src.Turn() != s.turn || (src != move.Piece && src.Promote() != move.Piece) {
+			return shogi.ErrPieceNotFound
+		}
+		if dst := s.board[i][j]; (dst != shogi.EMP && dst.Turn() == s.turn) || !s.reaches(ii, jj, i, j) {
+			return shogi.ErrUnreachable
+		}
+		if src != move.Piece {
+			if !isPromotable(src) || !(inEnemyCamp(ii, s.turn) || inEnemyCamp(i, s.turn)) {
+				return shogi.ErrInvalidPromotion
+			}
+		} else if !canMoveFurther(src, i) {
+			return shogi.ErrInvalidPromotion
+		}
+	}
+	if s.isSelfCheck(move) {
+		return shogi.ErrSelfCheck
+	}
+	return nil
+}
+
+func isPromotable(p shogi.Piece) bool {
+	if p.IsPromoted() {
+		return false
+	}
+	switch p.Raw() {
+	case shogi.FU, shogi.KY, shogi.KE, shogi.GI, shogi.KA, shogi.HI:
+		return true
+	}
+	return false
+}
+
+// canMoveFurther reports whether the piece on the rank i has any square to move next
+func canMoveFurther(p shogi.Piece, i int) bool {
+	if p.IsPromoted() {
+		return true
+	}
+	switch p.Raw() {
+	case shogi.FU, shogi.KY:
+		return (p.Turn() == shogi.TurnBlack && i > 0) || (p.Turn() == shogi.TurnWhite && i < 8)
+	case shogi.KE:
+		return (p.Turn() == shogi.TurnBlack && i > 1) || (p.Turn() == shogi.TurnWhite && i < 7)
+	}
+	return true
+}
@@ -16,6 +16,16 @@ type Move struct {
 	Piece Piece
 }
 
+var notationReplacer = strings.NewReplacer(
+	"１", "1", "２", "2", "３", "3", "４", "4", "５", "5", "６", "6", "７", "7", "８", "8", "９", "9",
+	"王", "玉", "龍", "竜", "杏", "成香", "圭", "成桂", "全", "成銀", "☗", "▲", "☖", "△",
+	" ", "", "　", "",
+)
+
+var relativeRemover = strings.NewReplacer(
+	"右", "", "左", "", "直", "", "上", "", "引", "", "寄", "", "打", "",
+)
+
 // MoveStrings function
 func MoveStrings(state State, moves ...*Move) ([]string, error) {
 	result := make([]string, 0, len(moves))
@@ -190,3 +200,103 @@ func moveString(state State, move, prev *Move) (string, error) {
 	}
 	return b.String(), nil
 }
+
+// ParseMoveString function returns the legal move of the state represented by the notation such as "▲７六歩".
+// prev is the previous move used for "同", and may be nil.
+func ParseMoveString(state State, s string, prev *Move) (*Move, error) {
+	token := notationReplacer.Replace(s)
+	runes := []rune(token)
+	if len(runes) == 0 {
+		return nil, ErrInvalidNotation
+	}
+	switch runes[0] {
+	case '▲', '△':
+		turn := TurnBlack
+		if runes[0] == '△' {
+			turn = TurnWhite
+		}
+		if turn != state.Turn() {
+			return nil, ErrWrongTurn
+		}
+	default:
+		switch state.Turn() {
+		case TurnBlack:
+			runes = append([]rune{'▲'}, runes...)
+		case TurnWhite:
+			runes = append([]rune{'△'}, runes...)
+		}
+		token = string(runes)
+	}
+	if len(runes) < 3 {
+		return nil, ErrInvalidNotation
+	}
+	var dst Position
+	if runes[1] == '同' {
+		if prev == nil {
+			return nil, ErrInvalidMove
+		}
+		dst = prev.Dst
+	} else {
+		file, rank := 0, 0
+		for i, r := range []rune("一二三四五六七八九") {
+			if r == runes[2] {
+				rank = i + 1
+			}
+		}
+		if runes[1] >= '1' && runes[1] <= '9' {
+			file = int(runes[1] - '0')
+		}
+		if file < 1 || rank < 1 {
+			return nil, ErrInvalidNotation
+		}
+		dst = Position{File: file, Rank: rank}
+	}
+	drop := strings.HasSuffix(token, "打")
+	found, loose := []*Move{}, []*Move{}
+	for _, m := range state.LegalMoves() {
+		if m.Dst != dst || (drop && m.Src != (Position{0, 0})) {
+			continue
+		}
+		notations := []string{}
+		for _, p := range []*Move{nil, prev} {
+			notation, err := moveString(state, m, p)
+			if err != nil {
+				return nil, err
+			}
+			notations = append(notations, notation)
+		}
+		for _, notation := range notations {
+			if notation == token {
+				found = append(found, m)
+				break
+			}
+		}
+		for _, notation := range notations {
+			if relativeRemover.Replace(notation) == relativeRemover.Replace(token) && containsRelatives(notation, token) {
+				loose = append(loose, m)
+				break
+			}
+		}
+	}
+	if len(found) == 1 {
+		return found[0], nil
+	}
+	// the notation omitting some of the relative notations
+	switch len(loose) {
+	case 0:
+		return nil, ErrInvalidMove
+	case 1:
+		return loose[0], nil
+	}
+	return nil, ErrAmbiguousMove
+}
+
+// containsRelatives reports whether the notation contains all relative notations of the token except "打"
+func containsRelatives(notation, token string) bool {
+	for _, r := range token {
+		if strings.ContainsRune("右左直上引寄", r) && !strings.ContainsRune(notation, r) {
+			return false
+		}
+	}
+	return true
+}
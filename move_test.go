@@ -53,7 +53,7 @@ func TestMove(t *testing.T) {
 				{Src: shogi.Position{File: 5, Rank: 9}, Dst: shogi.Position{File: 5, Rank: 8}, Piece: shogi.BFU},
 			},
 			result{
-				err: shogi.ErrInvalidMove,
+				err: shogi.ErrPieceNotFound,
 			},
 		},
 	}
@@ -102,7 +102,7 @@ func TestMoveStrings(t *testing.T) {
 				t.Errorf("#%d: move string got: %s, expected: %s", i, result, expected)
 				continue
 			}
-			t.Logf(result)
+			t.Log(result)
 		}
 	}
 	// 打
@@ -737,3 +737,52 @@ func TestMoveStrings(t *testing.T) {
 		}
 	}
 }
+
+func TestParseMoveString(t *testing.T) {
+	initial := logic.NewInitialState()
+	state := logic.NewInitialState()
+	prev := &shogi.Move{Src: shogi.Position{File: 8, Rank: 8}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.BUM}
+	if err := state.Move(
+		&shogi.Move{Src: shogi.Position{File: 7, Rank: 7}, Dst: shogi.Position{File: 7, Rank: 6}, Piece: shogi.BFU},
+		&shogi.Move{Src: shogi.Position{File: 3, Rank: 3}, Dst: shogi.Position{File: 3, Rank: 4}, Piece: shogi.WFU},
+		prev,
+	); err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		state    shogi.State
+		prev     *shogi.Move
+		s        string
+		expected *shogi.Move
+		err      error
+	}{
+		{initial, nil, "▲７六歩", &shogi.Move{Src: shogi.Position{File: 7, Rank: 7}, Dst: shogi.Position{File: 7, Rank: 6}, Piece: shogi.BFU}, nil},
+		{initial, nil, "☗7六歩", &shogi.Move{Src: shogi.Position{File: 7, Rank: 7}, Dst: shogi.Position{File: 7, Rank: 6}, Piece: shogi.BFU}, nil},
+		{initial, nil, "７六歩", &shogi.Move{Src: shogi.Position{File: 7, Rank: 7}, Dst: shogi.Position{File: 7, Rank: 6}, Piece: shogi.BFU}, nil},
+		{initial, nil, "▲５八金右", &shogi.Move{Src: shogi.Position{File: 4, Rank: 9}, Dst: shogi.Position{File: 5, Rank: 8}, Piece: shogi.BKI}, nil},
+		{initial, nil, "▲５八金", nil, shogi.ErrAmbiguousMove},
+		{initial, nil, "▲５八金直", nil, shogi.ErrInvalidMove},
+		{initial, nil, "▲７八金右", nil, shogi.ErrInvalidMove},
+		{initial, nil, "▲７八金左", nil, shogi.ErrInvalidMove},
+		{initial, nil, "▲７八金", &shogi.Move{Src: shogi.Position{File: 6, Rank: 9}, Dst: shogi.Position{File: 7, Rank: 8}, Piece: shogi.BKI}, nil},
+		{initial, nil, "△３四歩", nil, shogi.ErrWrongTurn},
+		{initial, nil, "▲７五歩", nil, shogi.ErrInvalidMove},
+		{initial, nil, "▲同歩", nil, shogi.ErrInvalidMove},
+		{initial, nil, "▲76", nil, shogi.ErrInvalidNotation},
+		{initial, nil, "abc", nil, shogi.ErrInvalidNotation},
+		{state, prev, "△同　銀", &shogi.Move{Src: shogi.Position{File: 3, Rank: 1}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.WGI}, nil},
+		{state, prev, "△２二銀", &shogi.Move{Src: shogi.Position{File: 3, Rank: 1}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.WGI}, nil},
+		{state, prev, "同銀", &shogi.Move{Src: shogi.Position{File: 3, Rank: 1}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.WGI}, nil},
+		{state, prev, "△８八角打", nil, shogi.ErrInvalidMove},
+	}
+	for i, tc := range testCases {
+		move, err := shogi.ParseMoveString(tc.state, tc.s, tc.prev)
+		if err != tc.err {
+			t.Errorf("#%d: %s: got error: %v, expected: %v", i, tc.s, err, tc.err)
+			continue
+		}
+		if tc.expected != nil && *move != *tc.expected {
+			t.Errorf("#%d: %s: got: %v, expected: %v", i, tc.s, move, tc.expected)
+		}
+	}
+}
@@ -1,13 +1,101 @@
 package shogi
 
+import (
+	"strings"
+	"time"
+)
+
+// TimeLayouts are the layouts of the date and time in the metadata, and the first one is used to write them
+var TimeLayouts = []string{
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/01/02",
+}
+
 // Player type
 type Player struct {
 	Name string
 }
 
+// Metadata type
+type Metadata struct {
+	Event     string
+	Site      string
+	StartTime time.Time
+	EndTime   time.Time
+	TimeLimit string
+	Opening   string
+	Handicap  string
+	Others    map[string]string
+}
+
+// ParseTime function parses the date and time in one of TimeLayouts, ignoring the day of the week such as "(土)"
+func ParseTime(s string) (time.Time, error) {
+	if i := strings.Index(s, "("); i >= 0 {
+		if j := strings.Index(s[i:], ")"); j >= 0 {
+			s = s[:i] + s[i+j+1:]
+		}
+	}
+	for _, layout := range TimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrInvalidTime
+}
+
+// Evaluation type holds the score and the principal variation reported by an engine
+type Evaluation struct {
+	Score int
+	PV    []*Move
+}
+
+// MoveInfo type holds the additional information of a move
+type MoveInfo struct {
+	Time       time.Duration
+	Comments   []string
+	Evaluation *Evaluation
+}
+
 // Record type
 type Record struct {
-	Players [2]*Player
-	State   State
-	Moves   []*Move
+	Players     [2]*Player
+	State       State
+	Moves       []*Move
+	MoveInfos   []*MoveInfo
+	Metadata    Metadata
+	Comments    []string
+	Result      Result
+	Termination Termination
+}
+
+// TotalTime method returns the cumulative consumed time of the player
+func (r *Record) TotalTime(turn Turn) time.Duration {
+	var total time.Duration
+	for i, move := range r.Moves {
+		if i >= len(r.MoveInfos) || r.MoveInfos[i] == nil {
+			break
+		}
+		if move.Piece.Turn() == turn {
+			total += r.MoveInfos[i].Time
+		}
+	}
+	return total
+}
+
+// IsCheckmate method reports whether the side to move is checkmated after the last move.
+// It returns false if the State is nil, and ErrNotSupported if the State does not implement CheckState.
+func (r *Record) IsCheckmate() (bool, error) {
+	if r.State == nil {
+		return false, nil
+	}
+	s := r.State.Clone()
+	if err := s.Move(r.Moves...); err != nil {
+		return false, err
+	}
+	c, ok := s.(CheckState)
+	if !ok {
+		return false, ErrNotSupported
+	}
+	return c.IsCheckmate(), nil
 }
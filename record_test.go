@@ -0,0 +1,45 @@
+package shogi_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/format/csa"
+)
+
+func TestRecordIsCheckmate(t *testing.T) {
+	testCases := []struct {
+		file     string
+		expected bool
+	}{
+		{"12.csa", true},
+		{"17.csa", false},
+		{"18.csa", false},
+		{"23.csa", true},
+		{"35.csa", true},
+		{"52.csa", false},
+	}
+	for i, tc := range testCases {
+		file, err := os.Open(filepath.Join("testdata", tc.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		record, err := csa.Parse(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := record.IsCheckmate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != tc.expected {
+			t.Errorf("#%d: %s got %v, expected: %v", i, tc.file, result, tc.expected)
+		}
+	}
+	if result, err := (&shogi.Record{}).IsCheckmate(); err != nil || result {
+		t.Errorf("got: %v, %v, expected: %v, %v", result, err, false, nil)
+	}
+}
@@ -0,0 +1,127 @@
+package shogi
+
+// Result type
+type Result int
+
+// Result constants
+const (
+	ResultNone     Result = iota
+	ResultBlackWin        // 先手勝ち
+	ResultWhiteWin        // 後手勝ち
+	ResultDraw            // 引き分け
+	ResultAborted         // 中断
+)
+
+// String method
+func (r Result) String() string {
+	switch r {
+	case ResultBlackWin:
+		return "black win"
+	case ResultWhiteWin:
+		return "white win"
+	case ResultDraw:
+		return "draw"
+	case ResultAborted:
+		return "aborted"
+	}
+	return "none"
+}
+
+// Winner method returns the turn of the side which wins the game
+func (r Result) Winner() (Turn, bool) {
+	switch r {
+	case ResultBlackWin:
+		return TurnBlack, true
+	case ResultWhiteWin:
+		return TurnWhite, true
+	}
+	return TurnBlack, false
+}
+
+// Termination type represents the reason why the game ended
+type Termination int
+
+// Termination constants
+const (
+	TerminationNone          Termination = iota
+	TerminationResign                    // 投了
+	TerminationAbort                     // 中断
+	TerminationSennichite                // 千日手
+	TerminationTimeUp                    // 切れ負け
+	TerminationIllegalMove               // 反則
+	TerminationIllegalAction             // 反則行為
+	TerminationDeclaration               // 入玉宣言勝ち
+	TerminationDraw                      // 引き分け
+	TerminationCheckmate                 // 詰み
+	TerminationNoCheckmate               // 不詰
+	TerminationError                     // エラー
+	TerminationMatta                     // 待った
+	TerminationMaxMoves                  // 最大手数
+	TerminationJishogi                   // 持将棋
+	TerminationForfeit                   // 不戦
+)
+
+// String method
+func (t Termination) String() string {
+	switch t {
+	case TerminationResign:
+		return "resign"
+	case TerminationAbort:
+		return "abort"
+	case TerminationSennichite:
+		return "sennichite"
+	case TerminationTimeUp:
+		return "time up"
+	case TerminationIllegalMove:
+		return "illegal move"
+	case TerminationIllegalAction:
+		return "illegal action"
+	case TerminationDeclaration:
+		return "declaration"
+	case TerminationDraw:
+		return "draw"
+	case TerminationCheckmate:
+		return "checkmate"
+	case TerminationNoCheckmate:
+		return "no checkmate"
+	case TerminationError:
+		return "error"
+	case TerminationMatta:
+		return "matta"
+	case TerminationMaxMoves:
+		return "max moves"
+	case TerminationJishogi:
+		return "jishogi"
+	case TerminationForfeit:
+		return "forfeit"
+	}
+	return "none"
+}
+
+// Result method returns the result of the game terminated by the side to move
+func (t Termination) Result(turn Turn) Result {
+	switch t {
+	case TerminationResign, TerminationTimeUp, TerminationIllegalMove, TerminationIllegalAction, TerminationCheckmate:
+		return LoseResult(turn)
+	case TerminationDeclaration:
+		return WinResult(turn)
+	case TerminationSennichite, TerminationDraw, TerminationMaxMoves, TerminationJishogi:
+		return ResultDraw
+	case TerminationAbort, TerminationError:
+		return ResultAborted
+	}
+	return ResultNone
+}
+
+// WinResult function returns the result which the turn wins
+func WinResult(turn Turn) Result {
+	if turn == TurnBlack {
+		return ResultBlackWin
+	}
+	return ResultWhiteWin
+}
+
+// LoseResult function returns the result which the turn loses
+func LoseResult(turn Turn) Result {
+	return WinResult(!turn)
+}
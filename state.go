@@ -42,3 +42,10 @@ type State interface {
 
 	String() string
 }
+
+// CheckState interface is optionally implemented by the State which can judge check and checkmate
+type CheckState interface {
+	InCheck() bool
+	Checkers() []Position
+	IsCheckmate() bool
+}
@@ -0,0 +1,207 @@
+package shogi
+
+import (
+	"regexp"
+	"strings"
+)
+
+// WesternStyle type
+type WesternStyle int
+
+// WesternStyle constants
+const (
+	WesternHodges  WesternStyle = iota // ranks as letters such as "P-7f"
+	WesternHosking                     // ranks as numbers such as "P-76"
+)
+
+var westernPieceLetters = map[RawPiece]string{
+	FU: "P",
+	KY: "L",
+	KE: "N",
+	GI: "S",
+	KI: "G",
+	KA: "B",
+	HI: "R",
+	OU: "K",
+}
+
+var westernMoveRegexp = regexp.MustCompile(`^(\+?[PLNSGBRKTHD])([1-9][a-i1-9])?([-x*]?)([1-9][a-i1-9])([+=]?)$`)
+
+// WesternMoveStrings function returns the moves in Western notation
+func WesternMoveStrings(state State, style WesternStyle, moves ...*Move) ([]string, error) {
+	result := make([]string, 0, len(moves))
+	s := state.Clone()
+	for _, m := range moves {
+		str, err := westernMoveString(s, m, style)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, str)
+		if err := s.Move(m); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func westernMoveString(state State, move *Move, style WesternStyle) (string, error) {
+	b := &strings.Builder{}
+	// drop
+	if move.Src == (Position{0, 0}) {
+		b.WriteString(westernPieceLetters[move.Piece.Raw()])
+		b.WriteRune('*')
+		b.WriteString(westernSquare(move.Dst, style))
+		return b.String(), nil
+	}
+
+	orig, err := state.GetPiece(move.Src.File, move.Src.Rank)
+	if err != nil {
+		return "", err
+	}
+	if orig.Raw() != move.Piece.Raw() {
+		return "", ErrInvalidMove
+	}
+	if orig.IsPromoted() {
+		b.WriteRune('+')
+	}
+	b.WriteString(westernPieceLetters[orig.Raw()])
+	// origin square only if another piece of the same kind can move to the destination
+	for _, m := range state.LegalMoves() {
+		if m.Src == (Position{0, 0}) || m.Src == move.Src || m.Dst != move.Dst {
+			continue
+		}
+		if p, _ := state.GetPiece(m.Src.File, m.Src.Rank); p == orig {
+			b.WriteString(westernSquare(move.Src, style))
+			break
+		}
+	}
+	captured, err := state.GetPiece(move.Dst.File, move.Dst.Rank)
+	if err != nil {
+		return "", err
+	}
+	if captured != EMP {
+		b.WriteRune('x')
+	} else {
+		b.WriteRune('-')
+	}
+	b.WriteString(westernSquare(move.Dst, style))
+	// promotion
+	if orig != move.Piece {
+		b.WriteRune('+')
+	} else if !orig.IsPromoted() {
+		switch orig.Raw() {
+		case FU, KY, KE, GI, KA, HI:
+			if (orig.Turn() == TurnBlack && (move.Src.Rank <= 3 || move.Dst.Rank <= 3)) ||
+				(orig.Turn() == TurnWhite && (move.Src.Rank >= 7 || move.Dst.Rank >= 7)) {
+				b.WriteRune('=')
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+func westernSquare(p Position, style WesternStyle) string {
+	if style == WesternHosking {
+		return string([]byte{byte('0' + p.File), byte('0' + p.Rank)})
+	}
+	return string([]byte{byte('0' + p.File), byte('a' + p.Rank - 1)})
+}
+
+func parseWesternSquare(s string) Position {
+	p := Position{File: int(s[0] - '0')}
+	if s[1] >= 'a' && s[1] <= 'i' {
+		p.Rank = int(s[1]-'a') + 1
+	} else {
+		p.Rank = int(s[1] - '0')
+	}
+	return p
+}
+
+// ParseWesternMoveString function returns the legal move of the state represented by Western notation
+// such as "P-7f", "Bx2b+", "S*5e", "G6h-5h" or "P-76"
+func ParseWesternMoveString(state State, s string) (*Move, error) {
+	m := westernMoveRegexp.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return nil, ErrInvalidNotation
+	}
+	letter := m[1]
+	switch letter {
+	case "T":
+		letter = "+P"
+	case "H":
+		letter = "+B"
+	case "D":
+		letter = "+R"
+	}
+	promoted := strings.HasPrefix(letter, "+")
+	var raw RawPiece
+	found := false
+	for r, l := range westernPieceLetters {
+		if l == strings.TrimPrefix(letter, "+") {
+			raw, found = r, true
+		}
+	}
+	if !found {
+		return nil, ErrInvalidNotation
+	}
+	piece := MakePiece(raw, state.Turn())
+	if promoted {
+		piece = piece.Promote()
+	}
+	dst := parseWesternSquare(m[4])
+	candidates := []*Move{}
+	for _, move := range state.LegalMoves() {
+		if move.Dst != dst {
+			continue
+		}
+		if move.Src == (Position{0, 0}) {
+			if (m[3] != "*" && m[3] != "") || m[2] != "" || m[5] != "" || move.Piece != piece {
+				continue
+			}
+		} else {
+			if m[3] == "*" {
+				continue
+			}
+			if m[2] != "" && move.Src != parseWesternSquare(m[2]) {
+				continue
+			}
+			orig, _ := state.GetPiece(move.Src.File, move.Src.Rank)
+			if orig != piece {
+				continue
+			}
+			// promotion is optional in the notation only when it is forced
+			promoted := orig != move.Piece
+			if (m[5] == "+" && !promoted) || (m[5] == "=" && promoted) || (m[5] == "" && promoted && !mustPromote(orig, dst)) {
+				continue
+			}
+			// "x" for a capture and "-" for a non-capture
+			captured, _ := state.GetPiece(dst.File, dst.Rank)
+			if (m[3] == "x" && captured == EMP) || (m[3] == "-" && captured != EMP) {
+				continue
+			}
+		}
+		candidates = append(candidates, move)
+	}
+	switch len(candidates) {
+	case 0:
+		return nil, ErrInvalidMove
+	case 1:
+		return candidates[0], nil
+	}
+	return nil, ErrAmbiguousMove
+}
+
+// mustPromote reports whether the piece has no square to move next from the destination without promotion
+func mustPromote(piece Piece, dst Position) bool {
+	rank := dst.Rank
+	if piece.Turn() == TurnWhite {
+		rank = 10 - rank
+	}
+	switch piece.Raw() {
+	case FU, KY:
+		return rank <= 1
+	case KE:
+		return rank <= 2
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package shogi_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sugyan/shogi"
+	"github.com/sugyan/shogi/format/csa"
+	"github.com/sugyan/shogi/format/sfen"
+	"github.com/sugyan/shogi/logic"
+)
+
+func TestWesternMoveStrings(t *testing.T) {
+	moves := []*shogi.Move{
+		{Src: shogi.Position{File: 7, Rank: 7}, Dst: shogi.Position{File: 7, Rank: 6}, Piece: shogi.BFU},
+		{Src: shogi.Position{File: 3, Rank: 3}, Dst: shogi.Position{File: 3, Rank: 4}, Piece: shogi.WFU},
+		{Src: shogi.Position{File: 8, Rank: 8}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.BUM},
+		{Src: shogi.Position{File: 3, Rank: 1}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.WGI},
+		{Src: shogi.Position{File: 0, Rank: 0}, Dst: shogi.Position{File: 4, Rank: 5}, Piece: shogi.BKA},
+		{Src: shogi.Position{File: 6, Rank: 1}, Dst: shogi.Position{File: 5, Rank: 2}, Piece: shogi.WKI},
+		{Src: shogi.Position{File: 4, Rank: 5}, Dst: shogi.Position{File: 6, Rank: 3}, Piece: shogi.BKA},
+	}
+	testCases := []struct {
+		style    shogi.WesternStyle
+		expected []string
+	}{
+		{shogi.WesternHodges, []string{"P-7f", "P-3d", "Bx2b+", "Sx2b", "B*4e", "G6a-5b", "Bx6c="}},
+		{shogi.WesternHosking, []string{"P-76", "P-34", "Bx22+", "Sx22", "B*45", "G61-52", "Bx63="}},
+	}
+	for i, tc := range testCases {
+		results, err := shogi.WesternMoveStrings(logic.NewInitialState(), tc.style, moves...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(results, tc.expected) {
+			t.Errorf("#%d: got: %v, expected: %v", i, results, tc.expected)
+		}
+	}
+}
+
+func TestParseWesternMoveString(t *testing.T) {
+	initial := logic.NewInitialState()
+	testCases := []struct {
+		s        string
+		expected *shogi.Move
+		err      error
+	}{
+		{"P-7f", &shogi.Move{Src: shogi.Position{File: 7, Rank: 7}, Dst: shogi.Position{File: 7, Rank: 6}, Piece: shogi.BFU}, nil},
+		{"P-76", &shogi.Move{Src: shogi.Position{File: 7, Rank: 7}, Dst: shogi.Position{File: 7, Rank: 6}, Piece: shogi.BFU}, nil},
+		{"P76", &shogi.Move{Src: shogi.Position{File: 7, Rank: 7}, Dst: shogi.Position{File: 7, Rank: 6}, Piece: shogi.BFU}, nil},
+		{"G4i-5h", &shogi.Move{Src: shogi.Position{File: 4, Rank: 9}, Dst: shogi.Position{File: 5, Rank: 8}, Piece: shogi.BKI}, nil},
+		{"G-5h", nil, shogi.ErrAmbiguousMove},
+		{"P-7e", nil, shogi.ErrInvalidMove},
+		{"P*7f", nil, shogi.ErrInvalidMove},
+		{"P-7f+", nil, shogi.ErrInvalidMove},
+		{"X-7f", nil, shogi.ErrInvalidNotation},
+		{"P-0f", nil, shogi.ErrInvalidNotation},
+	}
+	for i, tc := range testCases {
+		move, err := shogi.ParseWesternMoveString(initial, tc.s)
+		if err != tc.err {
+			t.Errorf("#%d: %s: got error: %v, expected: %v", i, tc.s, err, tc.err)
+			continue
+		}
+		if tc.expected != nil && *move != *tc.expected {
+			t.Errorf("#%d: %s: got: %v, expected: %v", i, tc.s, move, tc.expected)
+		}
+	}
+
+	// capture markers and promotions
+	captureState := logic.NewInitialState()
+	if err := captureState.Move(
+		&shogi.Move{Src: shogi.Position{File: 7, Rank: 7}, Dst: shogi.Position{File: 7, Rank: 6}, Piece: shogi.BFU},
+		&shogi.Move{Src: shogi.Position{File: 3, Rank: 3}, Dst: shogi.Position{File: 3, Rank: 4}, Piece: shogi.WFU},
+	); err != nil {
+		t.Fatal(err)
+	}
+	promotionState, _, err := sfen.Parse("4k4/P8/2N6/9/9/9/9/9/4K4 b - 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, tc := range []struct {
+		state    shogi.State
+		s        string
+		expected *shogi.Move
+		err      error
+	}{
+		{initial, "Px7f", nil, shogi.ErrInvalidMove},
+		{captureState, "Bx2b+", &shogi.Move{Src: shogi.Position{File: 8, Rank: 8}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.BUM}, nil},
+		{captureState, "B-2b+", nil, shogi.ErrInvalidMove},
+		{captureState, "B2b+", &shogi.Move{Src: shogi.Position{File: 8, Rank: 8}, Dst: shogi.Position{File: 2, Rank: 2}, Piece: shogi.BUM}, nil},
+		{promotionState, "P-9a", &shogi.Move{Src: shogi.Position{File: 9, Rank: 2}, Dst: shogi.Position{File: 9, Rank: 1}, Piece: shogi.BTO}, nil},
+		{promotionState, "P-9a+", &shogi.Move{Src: shogi.Position{File: 9, Rank: 2}, Dst: shogi.Position{File: 9, Rank: 1}, Piece: shogi.BTO}, nil},
+		{promotionState, "P-9a=", nil, shogi.ErrInvalidMove},
+		{promotionState, "N-8a", &shogi.Move{Src: shogi.Position{File: 7, Rank: 3}, Dst: shogi.Position{File: 8, Rank: 1}, Piece: shogi.BNK}, nil},
+		{promotionState, "N-6a=", nil, shogi.ErrInvalidMove},
+	} {
+		move, err := shogi.ParseWesternMoveString(tc.state, tc.s)
+		if err != tc.err {
+			t.Errorf("#%d: %s: got error: %v, expected: %v", i, tc.s, err, tc.err)
+			continue
+		}
+		if tc.expected != nil && *move != *tc.expected {
+			t.Errorf("#%d: %s: got: %v, expected: %v", i, tc.s, move, tc.expected)
+		}
+	}
+
+	// round trip
+	matches, err := filepath.Glob(filepath.Join("testdata", "*.csa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range matches {
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		record, err := csa.Parse(file)
+		file.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, style := range []shogi.WesternStyle{shogi.WesternHodges, shogi.WesternHosking} {
+			results, err := shogi.WesternMoveStrings(record.State, style, record.Moves...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			state := record.State.Clone()
+			for i, s := range results {
+				move, err := shogi.ParseWesternMoveString(state, s)
+				if err != nil {
+					t.Fatalf("%s: %d: %s: %v", path, i, s, err)
+				}
+				if *move != *record.Moves[i] {
+					t.Fatalf("%s: %d: %s: got: %v, expected: %v", path, i, s, move, record.Moves[i])
+				}
+				if err := state.Move(move); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+}